@@ -0,0 +1,286 @@
+package gounit
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrCheckFailed = GenericError("%d problem(s) found")
+)
+
+//Kinds of the problems found by Check
+const (
+	//ProblemOrphaned means that the function tested by the test no longer exists
+	ProblemOrphaned = "orphaned"
+
+	//ProblemStale means that the test doesn't match the signature of the tested function
+	ProblemStale = "stale"
+)
+
+//Problem is a problem of the test found by Check
+type Problem struct {
+	Kind     string
+	Test     string
+	Position token.Position
+	Message  string
+
+	//Warning is true if the problem may be a false positive, i.e. the
+	//orphaned test can be a hand-written test that doesn't test a function
+	Warning bool
+}
+
+//String implements fmt.Stringer
+func (p Problem) String() string {
+	if p.Warning {
+		return fmt.Sprintf("%s: warning: %s: %s test: %s", p.Position, p.Test, p.Kind, p.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s test: %s", p.Position, p.Test, p.Kind, p.Message)
+}
+
+var regexpWantField = regexp.MustCompile(`^want\d+$`)
+
+//Check looks for the problems of the tests generated by gounit in the package
+//located in the directory dir. A test is considered generated if it declares
+//a table of test cases in the "tests" variable. Test is orphaned if there is no
+//function in the package it could be generated for (see Func.TestName) and it's
+//stale if the fields of its args struct or its want fields don't match the
+//params and the results of the tested function. Tests are named according to the
+//naming scheme or the default scheme if it's nil. Orphaned tests are reported as
+//warnings since hand-written table tests, i.e. TestIntegration, look the same
+func Check(dir string, naming *NamingScheme) ([]Problem, error) {
+	fs := token.NewFileSet()
+
+	funcs := map[string]*Func{}
+	typeSpecs := map[string]*ast.TypeSpec{}
+
+	var (
+		pkgName string
+		all     []*Func
+	)
+
+	files := parsePackageFiles(fs, "", dir, "")
+	declared := declaredNames(files)
+
+	for _, file := range files {
+		pkgName = file.Name.Name
+
+		for name, spec := range findTypeSpecs(file.Decls) {
+			typeSpecs[name] = spec
+		}
+
+		for _, f := range findFunctions(file.Decls, func(*ast.FuncDecl) bool { return true }) {
+			f.naming = naming
+			f.declared = declared
+			funcs[f.TestName()] = f
+			all = append(all, f)
+		}
+	}
+
+	//tests named in the alternative styles don't take precedence over the tests
+	//named according to the naming scheme
+	for _, f := range all {
+		for _, name := range f.testNames() {
+			if _, ok := funcs[name]; !ok {
+				funcs[name] = f
+			}
+		}
+	}
+
+	for _, f := range all {
+		f.typeSpecs = typeSpecs
+	}
+
+	filter := func(fi os.FileInfo) bool {
+		return strings.HasSuffix(fi.Name(), "_test.go")
+	}
+
+	packages, err := parser.ParseDir(fs, dir, filter, 0)
+	if err != nil {
+		return nil, ErrFailedToParseOutFile.Format(err)
+	}
+
+	var problems []Problem
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, test := range findFunctions(file.Decls, isGeneratedTest) {
+				f, ok := funcs[test.Name()]
+				if !ok {
+					problems = append(problems, Problem{
+						Kind:     ProblemOrphaned,
+						Test:     test.Name(),
+						Position: fs.Position(test.Signature.Pos()),
+						Message:  "tested function not found",
+						Warning:  true,
+					})
+					continue
+				}
+
+				//types of the source package are qualified in the external test package
+				qualifier := ""
+				if file.Name.Name == pkgName+"_test" {
+					qualifier = pkgName + "."
+				}
+
+				for _, msg := range f.checkTest(fs, test.Signature, qualifier) {
+					problems = append(problems, Problem{
+						Kind:     ProblemStale,
+						Test:     test.Name(),
+						Position: fs.Position(test.Signature.Pos()),
+						Message:  msg,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		pi, pj := problems[i].Position, problems[j].Position
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Line < pj.Line
+	})
+
+	return problems, nil
+}
+
+//checkTest returns descriptions of the mismatches between the
+//test and the signature of the function
+func (f *Func) checkTest(fs *token.FileSet, test *ast.FuncDecl, qualifier string) []string {
+	var (
+		messages  []string
+		args      = findStructType(test.Body, "args")
+		testCases = findTestCasesType(test.Body)
+		typeOf    = func(field *ast.Field) string {
+			return nodeToString(fs, field.Type)
+		}
+	)
+
+	if qualifier != "" {
+		re := regexp.MustCompile(`\b` + regexp.QuoteMeta(qualifier))
+		typeOf = func(field *ast.Field) string {
+			return re.ReplaceAllString(nodeToString(fs, field.Type), "")
+		}
+	}
+
+	if args == nil && testCases != nil && f.NumParams() > 0 {
+		messages = append(messages, fmt.Sprintf("args struct is missing, params are {%s}", strings.Join(f.Params(fs), ", ")))
+	}
+
+	if args != nil {
+		var got []string
+		for _, field := range args.Fields.List {
+			for _, name := range field.Names {
+				got = append(got, name.Name+" "+typeOf(field))
+			}
+		}
+
+		if want := f.Params(fs); strings.Join(got, ", ") != strings.Join(want, ", ") {
+			messages = append(messages, fmt.Sprintf("args {%s} don't match params {%s}", strings.Join(got, ", "), strings.Join(want, ", ")))
+		}
+	}
+
+	if testCases == nil {
+		return messages
+	}
+
+	var (
+		wants   []string
+		wantErr bool
+	)
+
+	for _, field := range testCases.Fields.List {
+		for _, name := range field.Names {
+			switch {
+			case name.Name == "wantErr":
+				wantErr = true
+			case regexpWantField.MatchString(name.Name):
+				wants = append(wants, name.Name+" "+typeOf(field))
+			}
+		}
+	}
+
+	var results []string
+	for _, result := range f.Results(fs) {
+		results = append(results, strings.Replace(result, "got", "want", 1))
+	}
+
+	if strings.Join(wants, ", ") != strings.Join(results, ", ") {
+		messages = append(messages, fmt.Sprintf("want fields {%s} don't match results {%s}", strings.Join(wants, ", "), strings.Join(results, ", ")))
+	}
+
+	switch {
+	case wantErr && !f.ReturnsError():
+		messages = append(messages, "wantErr field is declared but the function doesn't return an error")
+	case !wantErr && f.ReturnsError():
+		messages = append(messages, "the function returns an error but wantErr field is not declared")
+	}
+
+	return messages
+}
+
+//isGeneratedTest returns true if the function is a test that declares a table of test cases
+func isGeneratedTest(fd *ast.FuncDecl) bool {
+	if fd.Recv != nil || fd.Body == nil || !strings.HasPrefix(fd.Name.Name, "Test") || fd.Name.Name == "TestMain" {
+		return false
+	}
+
+	return findTestCasesType(fd.Body) != nil
+}
+
+//findStructType returns a struct type declared in the block with the given name
+func findStructType(body *ast.BlockStmt, name string) *ast.StructType {
+	for _, stmt := range body.List {
+		decl, ok := stmt.(*ast.DeclStmt)
+		if !ok {
+			continue
+		}
+
+		if spec := findTypeSpecs([]ast.Decl{decl.Decl})[name]; spec != nil {
+			st, _ := spec.Type.(*ast.StructType)
+			return st
+		}
+	}
+
+	return nil
+}
+
+//findTestCasesType returns a type of the test case declared as "tests := []struct{...}{...}"
+func findTestCasesType(body *ast.BlockStmt) *ast.StructType {
+	lit := findTestCasesLit(body)
+	if lit == nil {
+		return nil
+	}
+
+	if array, ok := lit.Type.(*ast.ArrayType); ok {
+		st, _ := array.Elt.(*ast.StructType)
+		return st
+	}
+
+	return nil
+}
+
+//findTestCasesLit returns a composite literal of the test cases declared as "tests := []struct{...}{...}"
+func findTestCasesLit(body *ast.BlockStmt) *ast.CompositeLit {
+	for _, stmt := range body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+
+		if ident, ok := assign.Lhs[0].(*ast.Ident); ok && ident.Name == "tests" {
+			lit, _ := assign.Rhs[0].(*ast.CompositeLit)
+			return lit
+		}
+	}
+
+	return nil
+}
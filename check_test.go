@@ -0,0 +1,107 @@
+package gounit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const checkSource = `package check
+
+type T struct{}
+
+func (t *T) Method(a int) (string, error) { return "", nil }
+
+func Renamed(a, b int) int { return a + b }
+
+func Fine(s []string, opts ...int) (n int, err error) { return 0, nil }
+`
+
+const checkTestSource = `package check
+
+import "testing"
+
+func TestT_Method(t *testing.T) {
+	type args struct {
+		a int
+	}
+	tests := []struct {
+		name    string
+		args    func(t *testing.T) args
+		want1   int
+		wantErr bool
+	}{}
+	_ = tests
+}
+
+func TestOld(t *testing.T) {
+	tests := []struct {
+		name string
+	}{}
+	_ = tests
+}
+
+func TestRenamed(t *testing.T) {
+	tests := []struct {
+		name  string
+		want1 int
+	}{}
+	_ = tests
+}
+
+func TestFine(t *testing.T) {
+	type args struct {
+		s    []string
+		opts []int
+	}
+	tests := []struct {
+		name    string
+		want1   int
+		wantErr bool
+	}{}
+	_ = tests
+}
+
+func TestHandWritten(t *testing.T) {}
+`
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_check")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, src := range map[string]string{"check.go": checkSource, "check_test.go": checkTestSource} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	got1, err := Check(dir, nil)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	want1 := []string{
+		"TestT_Method: stale test: want fields {want1 int} don't match results {want1 string}",
+		"warning: TestOld: orphaned test: tested function not found",
+		"TestRenamed: stale test: args struct is missing, params are {a int, b int}",
+	}
+
+	if len(got1) != len(want1) {
+		t.Fatalf("Check got1 = %v, want1: %v", got1, want1)
+	}
+
+	for i, p := range got1 {
+		if !strings.HasSuffix(p.String(), want1[i]) {
+			t.Errorf("Check got1[%d] = %q, want1: %q", i, p, want1[i])
+		}
+
+		if p.Warning != (p.Kind == ProblemOrphaned) {
+			t.Errorf("Check got1[%d] = %q, orphaned tests must be warnings", i, p)
+		}
+	}
+}
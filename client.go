@@ -1,8 +1,13 @@
 package gounit
 
+import "encoding/json"
+
 //Request is a JSON object that is read from Stdin
 //in JSON mode (see -json command line flag)
 type Request struct {
+	//ID is an optional identifier of the request that is echoed back in the Response
+	ID json.RawMessage `json:"id,omitempty"`
+
 	InputFilePath  string `json:"inputFilePath"`
 	OutputFilePath string `json:"outputFilePath"`
 	InputFile      string `json:"inputFile"`
@@ -12,8 +17,36 @@ type Request struct {
 	Lines          []int  `json:"lines"`
 }
 
+//Kinds of errors reported in the Response
+const (
+	//ErrorKindRequest means that the request can't be decoded
+	ErrorKindRequest = "request"
+
+	//ErrorKindTemplate means that the requested template can't be found
+	ErrorKindTemplate = "template"
+
+	//ErrorKindFuncNotFound means that there are no functions declared at the requested lines
+	ErrorKindFuncNotFound = "funcNotFound"
+
+	//ErrorKindGenerator means that the input file, the output file
+	//or the template can't be parsed
+	ErrorKindGenerator = "generator"
+
+	//ErrorKindWrite means that the generated code can't be written or formatted
+	ErrorKindWrite = "write"
+)
+
 //Response is a JSON object that is written to Stdout
 //in JSON mode
 type Response struct {
+	//ID is an identifier of the request this response is for
+	ID json.RawMessage `json:"id,omitempty"`
+
 	GeneratedCode string `json:"generatedCode"`
+
+	//Error is a description of the error occurred while processing the request
+	Error string `json:"error,omitempty"`
+
+	//ErrorKind is one of ErrorKind* constants
+	ErrorKind string `json:"errorKind,omitempty"`
 }
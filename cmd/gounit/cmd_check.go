@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/hexdigest/gounit"
+)
+
+//CheckCommand implements Command interface
+type CheckCommand struct {
+	fs *flag.FlagSet
+}
+
+//Description implements Command interface
+func (cc *CheckCommand) Description() string {
+	return "find orphaned and stale tests"
+}
+
+func (cc *CheckCommand) Usage() string {
+	return `usage: gounit check [packages]
+
+Check looks for the problems of the tests generated by gounit in the given
+packages (current directory by default). Packages are directories or patterns
+like ./... and ./pkg/... that match directories recursively.
+
+Orphaned tests are the tests of the functions that no longer exist, i.e.
+TestFoo_Bar when there is no method Bar of type Foo. Stale tests are the tests
+whose args struct fields or want fields don't match the params and the results
+of the tested function. Orphaned tests are reported as warnings since they can
+be hand-written table tests. Exit code is 1 if any stale tests are found.
+`
+}
+
+func (cc *CheckCommand) FlagSet() *flag.FlagSet {
+	if cc.fs == nil {
+		cc.fs = &flag.FlagSet{}
+	}
+
+	return cc.fs
+}
+
+func (cc *CheckCommand) Run(args []string, stdout, stderr io.Writer) error {
+	if err := cc.FlagSet().Parse(args); err != nil {
+		return gounit.CommandLineError(err.Error())
+	}
+
+	patterns := cc.FlagSet().Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	files, err := expandPatterns(patterns)
+	if err != nil {
+		return err
+	}
+
+	var (
+		dirs     []string
+		seen     = map[string]bool{}
+		problems int
+	)
+
+	for _, file := range files {
+		if dir := filepath.Dir(file); !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, dir := range dirs {
+		config, err := gounit.LoadProjectConfig(dir)
+		if err != nil {
+			return err
+		}
+
+		var naming *gounit.NamingScheme
+		if config.Naming != "" {
+			if naming, err = gounit.NewNamingScheme(config.Naming); err != nil {
+				return err
+			}
+		}
+
+		found, err := gounit.Check(dir, naming)
+		if err != nil {
+			return fmt.Errorf("%s: %v", dir, err)
+		}
+
+		for _, p := range found {
+			fmt.Fprintln(stdout, p)
+			if !p.Warning {
+				problems++
+			}
+		}
+	}
+
+	if problems > 0 {
+		return gounit.ErrCheckFailed.Format(problems)
+	}
+
+	return nil
+}
@@ -6,23 +6,33 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/hexdigest/gounit"
 )
 
 type LinesNumbers []int
 type FunctionsList []string
+type PatternsList []string
 
 //GenerateCommand implements Command interface
 type GenerateCommand struct {
-	Options gounit.Options
-	fs      *flag.FlagSet
-	lines   LinesNumbers
-	funcs   FunctionsList
+	Options  gounit.Options
+	fs       *flag.FlagSet
+	lines    LinesNumbers
+	funcs    PatternsList
+	bench    bool
+	fuzz     bool
+	examples bool
+
+	coverProfile string
 }
 
 //Description implements Command interface
@@ -31,7 +41,38 @@ func (gc *GenerateCommand) Description() string {
 }
 
 func (gc *GenerateCommand) Usage() string {
-	return "usage: gounit gen [-i input file] [-o output file] [-t template name] [-all | -l lines | -f functions]"
+	return `usage: gounit gen [-i input file] [-o output file] [-t template name] [-bench | -fuzz | -examples] [-x] [-update] [-diff | -dry-run] [-all | -l lines | -f functions] [filters] [-coverprofile file]
+       gounit gen [-t template name] [-bench | -fuzz | -examples] [-x] [-update] [-diff | -dry-run] [-all | -f functions] [filters] [-coverprofile file] packages
+
+Filters: [-exported | -unexported] [-methods | -funcs] [-type T]
+
+Packages are directories or patterns like ./... and ./pkg/... that match
+directories recursively. Tests are generated for every non-test .go file
+of the matching packages and written into the corresponding _test.go files.
+
+With -diff or -dry-run flags output files are left untouched: -diff prints
+a unified diff of the changes and -dry-run prints names of the tests that
+would be generated.
+
+With -bench flag benchmarks are generated rather than tests and with -fuzz
+flag fuzz tests are generated for the functions whose params are all fuzzable
+(strings, []byte, integers, floats and bools). With -examples flag examples of
+the exported functions are written to example_test.go unless -o flag is given.
+These templates can be redefined in the custom template: {{define "bench"}}...{{end}},
+{{define "fuzz"}}...{{end}} and {{define "example"}}...{{end}}
+
+Functions passed with -f flag are names or patterns: globs like Get* or
+Service.* (methods match both Method and Type.Method) and regular expressions
+enclosed in slashes like /^(Get|Set)/. Filters narrow down the functions
+selected by -all, -l and -f flags, i.e. "gounit gen -exported -type Service"
+generates tests for all exported methods of the Service type.
+
+With -coverprofile flag tests are generated for the functions that have no
+statements covered according to the profile produced by go test -coverprofile,
+or whose coverage is below the -coverthreshold percentage, even if the tests
+with the same names exist. The names of the new tests get the _coverage suffix
+when they clash with the existing tests, so TestFoo_coverage is generated once.
+`
 }
 
 func (gc *GenerateCommand) FlagSet() *flag.FlagSet {
@@ -43,12 +84,29 @@ func (gc *GenerateCommand) FlagSet() *flag.FlagSet {
 		gc.fs.BoolVar(&o.UseJSON, "json", false, "read JSON-encoded input parameters from stdin\nplease see http://github.com/hexdigest/gounit for details")
 		gc.fs.BoolVar(&o.UseStdin, "stdin", false, "use stdin rather than reading the input file")
 		gc.fs.BoolVar(&o.UseStdout, "stdout", false, "use stdout rather than writing to the output file")
+		gc.fs.BoolVar(&o.Diff, "diff", false, "print a unified diff between the output file and the generated code\nrather than writing to the output file")
+		gc.fs.BoolVar(&o.DryRun, "dry-run", false, "print names of the tests that would be generated\nrather than writing to the output file")
+		gc.fs.IntVar(&o.Concurrency, "j", 1, "number of JSON requests processed concurrently, responses\nare written in the order of completion when it's greater than 1")
+		gc.fs.BoolVar(&gc.bench, "bench", false, "generate benchmarks rather than tests")
+		gc.fs.BoolVar(&gc.fuzz, "fuzz", false, "generate fuzz tests rather than tests for the functions with fuzzable params")
+		gc.fs.BoolVar(&gc.examples, "examples", false, "generate examples of the exported functions rather than tests,\nexamples are written to example_test.go by default")
+		gc.fs.BoolVar(&o.Update, "update", false, "update existing tests that don't match the signatures of the tested functions,\ntest cases of the updated tests are preserved")
+		gc.fs.BoolVar(&o.ExternalPackage, "x", false, "generate black-box tests in the external test package (i.e. package foo_test),\nunexported functions are skipped")
+		gc.fs.BoolVar(&o.TypeCheck, "types", false, "load the source package with the type information\nso templates can use resolved types of params and results")
 		gc.fs.StringVar(&o.InputFile, "i", "", "input file name")
 		gc.fs.StringVar(&o.OutputFile, "o", "", "output file name (optional)")
 		gc.fs.StringVar(&o.TemplateName, "t", "", "name of the template to use for the code generation (optional)")
 		gc.fs.StringVar(&o.Comment, "c", "", "comment that will be inserted into the generated test")
+		gc.fs.StringVar(&gc.coverProfile, "coverprofile", "", "generate tests for the functions that aren't covered according to the\ncoverage profile written by go test -coverprofile")
+		gc.fs.Float64Var(&o.CoverThreshold, "coverthreshold", 0, "with -coverprofile flag also generate tests for the functions\nwhose coverage percentage is below the threshold")
+		gc.fs.StringVar(&o.Naming, "naming", "", "naming scheme of the tests, i.e. \"Test{{.Type}}{{.Name}}\", it's used to name\nthe new tests and to find the existing ones")
 		gc.fs.Var(&gc.lines, "l", "comma-separated line numbers (starting with 1) to look for the function declarations")
-		gc.fs.Var(&gc.funcs, "f", "comma-separated function names to generate tests for")
+		gc.fs.Var(&gc.funcs, "f", "comma-separated function names or patterns to generate tests for")
+		gc.fs.BoolVar(&o.Exported, "exported", false, "generate tests only for the exported functions")
+		gc.fs.BoolVar(&o.Unexported, "unexported", false, "generate tests only for the unexported functions")
+		gc.fs.BoolVar(&o.Methods, "methods", false, "generate tests only for the methods")
+		gc.fs.BoolVar(&o.Funcs, "funcs", false, "generate tests only for the functions that aren't methods")
+		gc.fs.StringVar(&o.ReceiverType, "type", "", "generate tests only for the methods of the type")
 	}
 
 	return gc.fs
@@ -59,150 +117,446 @@ func (gc *GenerateCommand) Run(args []string, stdout, stderr io.Writer) error {
 		return gounit.CommandLineError(err.Error())
 	}
 
+	kinds := map[gounit.Kind]bool{
+		gounit.KindBenchmark: gc.bench,
+		gounit.KindFuzz:      gc.fuzz,
+		gounit.KindExample:   gc.examples,
+	}
+
+	for kind, enabled := range kinds {
+		if !enabled {
+			continue
+		}
+
+		if gc.Options.Kind != "" && gc.Options.Kind != kind {
+			return gounit.CommandLineError("-bench, -fuzz and -examples flags can't be used together")
+		}
+
+		gc.Options.Kind = kind
+	}
+
+	switch o := gc.Options; {
+	case o.Exported && o.Unexported:
+		return gounit.CommandLineError("-exported and -unexported flags can't be used together")
+	case o.Funcs && (o.Methods || o.ReceiverType != ""):
+		return gounit.CommandLineError("-funcs flag can't be used along with -methods and -type flags")
+	}
+
+	if gc.coverProfile != "" {
+		switch {
+		case gc.Options.Kind != "" && gc.Options.Kind != gounit.KindTest:
+			return gounit.CommandLineError("-coverprofile flag can't be used along with -bench, -fuzz and -examples flags")
+		case gc.Options.Update:
+			return gounit.CommandLineError("-coverprofile flag can't be used along with -update flag")
+		}
+
+		profile, err := readCoverProfile(gc.coverProfile)
+		if err != nil {
+			return err
+		}
+
+		gc.Options.CoverProfile = profile
+	}
+
 	options := gc.Options
 	options.Lines = []int(gc.lines)
 	options.Functions = []string(gc.funcs)
 
 	options.All = (len(options.Lines) == 0 && len(options.Functions) == 0)
+	if patterns := gc.FlagSet().Args(); len(patterns) > 0 {
+		return gc.generatePackages(options, patterns, stdout, stderr)
+	}
+
+	//file paths are passed in the JSON requests
+	if options.UseJSON {
+		return gc.processJSON(options, os.Stdin, stdout)
+	}
 
 	if options.InputFile == "" {
 		return gounit.CommandLineError("missing input file")
 	}
 
+	options, err := gc.projectOptions(options, options.InputFile)
+	if err != nil {
+		return err
+	}
+
 	if options.OutputFile == "" {
-		if strings.HasSuffix(options.InputFile, ".go") {
-			chunks := strings.Split(options.InputFile, ".")
-			options.OutputFile = strings.Join(chunks[0:len(chunks)-1], ".")
-		} else {
-			options.OutputFile = options.InputFile
-		}
-		options.OutputFile += "_test.go"
+		options.OutputFile = kindFileName(options.InputFile, options.Kind)
 	}
 
-	if options.UseJSON {
-		if err := gc.processJSON(os.Stdin, stdout); err != nil {
+	_, err = gc.generate(options, stdout, stderr)
+	return err
+}
+
+//generatePackages generates tests for every non-test .go file matching the
+//given package patterns and prints a summary of the created tests
+func (gc *GenerateCommand) generatePackages(options gounit.Options, patterns []string, stdout, stderr io.Writer) error {
+	if options.InputFile != "" || options.OutputFile != "" {
+		return gounit.CommandLineError("-i and -o flags can't be used along with package patterns")
+	}
+
+	if options.UseJSON || options.UseStdin {
+		return gounit.CommandLineError("-json and -stdin flags can't be used along with package patterns")
+	}
+
+	files, err := expandPatterns(patterns)
+	if err != nil {
+		return err
+	}
+
+	//generated code goes to stdout so the summary is written to stderr
+	summary := stdout
+	if options.UseStdout || options.Diff || options.DryRun {
+		summary = stderr
+	}
+
+	var numTests, numFiles int
+	for _, file := range files {
+		opt, err := gc.projectOptions(options, file)
+		if err != nil {
 			return err
 		}
+
+		opt.InputFile = file
+		if opt.OutputFile == "" {
+			opt.OutputFile = kindFileName(file, opt.Kind)
+		}
+
+		n, err := gc.generate(opt, stdout, stderr)
+		if err == gounit.ErrFuncNotFound {
+			continue
+		}
+
+		//packages without tests may be missing in the profile
+		if err == gounit.ErrNotInCoverProfile {
+			fmt.Fprintf(stderr, "%s: skipped: %v\n", file, err)
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+
+		if n > 0 {
+			//names of the tests are already printed in dry-run mode
+			if !options.DryRun {
+				fmt.Fprintf(summary, "%s: %d test(s) generated\n", opt.OutputFile, n)
+			}
+			numTests += n
+			numFiles++
+		}
+	}
+
+	verb := "generated"
+	if options.DryRun {
+		verb = "would be generated"
 	}
 
+	fmt.Fprintf(summary, "%d test(s) %s in %d file(s)\n", numTests, verb, numFiles)
+
+	return nil
+}
+
+//generate generates tests for a single input file and returns
+//a number of generated tests
+func (gc *GenerateCommand) generate(options gounit.Options, stdout, stderr io.Writer) (int, error) {
 	var (
 		r, testSrc io.Reader
-		w          io.WriteCloser
+		w          io.Writer
 		err        error
 		buf        = bytes.NewBuffer([]byte{})
 	)
 
-	r, err = os.Open(options.InputFile)
+	inFile, err := os.Open(options.InputFile)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return gounit.ErrFailedToOpenInFile.Format(err)
+			return 0, gounit.ErrFailedToOpenInFile.Format(err)
 		}
 
 		if !options.UseStdin {
-			return gounit.ErrInputFileDoesNotExist
+			return 0, gounit.ErrInputFileDoesNotExist
 		}
 
 		r = os.Stdin
+	} else {
+		defer inFile.Close()
+		r = inFile
 	}
 
 	outFile, err := os.OpenFile(options.OutputFile, os.O_RDWR, 0600)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			gounit.ErrFailedToOpenOutFile.Format(err)
+			return 0, gounit.ErrFailedToOpenOutFile.Format(err)
 		}
 	} else {
+		defer outFile.Close()
 		w = outFile
 		testSrc = outFile
 	}
 
 	options.Template, err = getTemplate(options.TemplateName)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	options.LoadTemplate = getTemplate
 
 	generator, err := gounit.NewGenerator(options, r, testSrc)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if err := generator.Write(buf); err != nil {
-		return err
+	for _, f := range generator.Skipped() {
+		name := f.Name()
+		if f.IsMethod() {
+			name = f.ReceiverTypeName() + "." + name
+		}
+
+		reason := "unexported functions can't be tested from the external test package"
+		switch {
+		case f.IsSkipped():
+			reason = "marked with //gounit:skip directive"
+		case options.Kind == gounit.KindFuzz && !f.IsFuzzable():
+			reason = "params of the function can't be fuzzed"
+		case f.IsExported() && len(f.UnexportedTypes()) > 0:
+			reason = "signature references unexported types: " + strings.Join(f.UnexportedTypes(), ", ")
+		case options.Kind == gounit.KindExample:
+			reason = "examples are generated only for the exported functions"
+		}
+
+		fmt.Fprintf(stderr, "%s: %s skipped: %s\n", options.OutputFile, name, reason)
 	}
 
-	//rewind output file back to write from the beginning without
-	//re-opening the file
-	if seeker, ok := w.(io.Seeker); ok {
-		if _, err := seeker.Seek(0, 0); err != nil {
-			return gounit.ErrSeekFailed.Format(err)
+	if options.DryRun {
+		for _, f := range generator.Funcs() {
+			fmt.Fprintf(stdout, "%s: %s\n", options.OutputFile, options.Kind.FuncName(f))
+		}
+
+		for _, f := range generator.Updated() {
+			fmt.Fprintf(stdout, "%s: %s (update)\n", options.OutputFile, f.TestName())
 		}
+
+		return len(generator.Funcs()) + len(generator.Updated()), nil
+	}
+
+	//original contents of the output file
+	original := generator.Source()
+
+	if err := generator.Write(buf); err != nil {
+		return 0, err
+	}
+
+	if options.Diff {
+		return len(generator.Funcs()) + len(generator.Updated()), writeDiff(stdout, options.OutputFile, original, buf.Bytes())
 	}
 
 	if options.UseStdout {
-		w = os.Stdout
+		w = stdout
+	} else if outFile != nil && buf.Len() > 0 {
+		//rewind output file back to write from the beginning without
+		//re-opening the file, updated tests can make it shorter
+		if _, err := outFile.Seek(0, 0); err != nil {
+			return 0, gounit.ErrSeekFailed.Format(err)
+		}
+
+		if err := outFile.Truncate(0); err != nil {
+			return 0, gounit.ErrWriteTest.Format(err)
+		}
 	}
 
 	if b := buf.Bytes(); len(b) > 0 { //some code has been generated
 		if w == nil {
-			if w, err = os.OpenFile(options.OutputFile, os.O_CREATE|os.O_WRONLY, 0600); err != nil {
-				gounit.ErrFailedToCreateOutFile.Format(err)
+			f, err := os.OpenFile(options.OutputFile, os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				return 0, gounit.ErrFailedToCreateOutFile.Format(err)
 			}
-			defer w.Close()
+			defer f.Close()
+			w = f
 		}
 
 		if _, err = w.Write(b); err != nil {
-			gounit.ErrWriteTest.Format(err)
+			return 0, gounit.ErrWriteTest.Format(err)
 		}
 	}
 
-	return nil
+	return len(generator.Funcs()) + len(generator.Updated()), nil
 }
 
-func (gc *GenerateCommand) processJSON(r io.Reader, w io.Writer) error {
-	var jo gounit.Request
+//processJSON reads requests from r and writes responses to w until r is exhausted.
+//Failed requests are reported in the responses and don't interrupt the processing.
+//Requests are processed by gc.Options.Concurrency workers so the responses
+//are written in the order of completion unless there is a single worker
+func (gc *GenerateCommand) processJSON(options gounit.Options, r io.Reader, w io.Writer) error {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		encErr   error
+		encoder  = json.NewEncoder(w)
+		decoder  = json.NewDecoder(r)
+		requests = make(chan gounit.Request)
+		workers  = options.Concurrency
+	)
 
-	encoder := json.NewEncoder(w)
-	decoder := json.NewDecoder(r)
+	encode := func(response gounit.Response) error {
+		mu.Lock()
+		defer mu.Unlock()
 
-	for {
-		err := decoder.Decode(&jo)
-		if err != nil {
-			return err
+		if encErr == nil {
+			encErr = encoder.Encode(response)
 		}
 
-		inputFile := strings.NewReader(jo.InputFile)
+		return encErr
+	}
 
-		var outputFile io.Reader
-		if len(jo.OutputFile) > 0 {
-			outputFile = strings.NewReader(jo.OutputFile)
-		}
+	if workers < 1 {
+		workers = 1
+	}
 
-		opt := gounit.Options{
-			InputFile:  jo.InputFilePath,
-			OutputFile: jo.OutputFilePath,
-			Comment:    jo.Comment,
-			Lines:      jo.Lines,
-		}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-		opt.Template, err = getTemplate(jo.TemplateName)
-		if err != nil {
-			return err
-		}
+			for jo := range requests {
+				response := gc.processRequest(options, jo)
+				response.ID = jo.ID
+				encode(response)
+			}
+		}()
+	}
 
-		generator, err := gounit.NewGenerator(opt, inputFile, outputFile)
-		if err != nil {
-			return err
-		}
+	err := func() error {
+		for {
+			var raw json.RawMessage
 
-		b := bytes.NewBuffer([]byte{})
+			//decoder can't recover from the malformed JSON
+			if err := decoder.Decode(&raw); err != nil {
+				if err == io.EOF {
+					return nil
+				}
 
-		if err := generator.Write(b); err != nil {
-			return err
+				encode(errorResponse(gounit.ErrorKindRequest, err))
+				return err
+			}
+
+			var jo gounit.Request
+			if err := json.Unmarshal(raw, &jo); err != nil {
+				//trying to get at least an ID of the invalid request
+				var invalid struct {
+					ID json.RawMessage `json:"id"`
+				}
+				json.Unmarshal(raw, &invalid)
+
+				response := errorResponse(gounit.ErrorKindRequest, err)
+				response.ID = invalid.ID
+
+				if err := encode(response); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			mu.Lock()
+			failed := encErr != nil
+			mu.Unlock()
+
+			if failed {
+				return nil
+			}
+
+			requests <- jo
 		}
+	}()
 
-		if err := encoder.Encode(gounit.Response{GeneratedCode: b.String()}); err != nil {
-			return err
+	close(requests)
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	return encErr
+}
+
+//processRequest generates tests for a single JSON request. Options given
+//in the command line apply to every request unless they are overridden by
+//the request, options of the project config apply unless the flags are set
+func (gc *GenerateCommand) processRequest(options gounit.Options, jo gounit.Request) gounit.Response {
+	inputFile := strings.NewReader(jo.InputFile)
+
+	var outputFile io.Reader
+	if len(jo.OutputFile) > 0 {
+		outputFile = strings.NewReader(jo.OutputFile)
+	}
+
+	opt := options
+	if jo.InputFilePath != "" {
+		var err error
+		if opt, err = gc.projectOptions(opt, jo.InputFilePath); err != nil {
+			return errorResponse(gounit.ErrorKindRequest, err)
 		}
 	}
+
+	opt.InputFile = jo.InputFilePath
+	opt.OutputFile = jo.OutputFilePath
+	opt.Lines = jo.Lines
+	opt.All = len(opt.Lines) == 0 && len(opt.Functions) == 0
+
+	if jo.Comment != "" {
+		opt.Comment = jo.Comment
+	}
+
+	templateName := opt.TemplateName
+	if jo.TemplateName != "" {
+		templateName = jo.TemplateName
+	}
+
+	var err error
+	opt.Template, err = getTemplate(templateName)
+	if err != nil {
+		return errorResponse(gounit.ErrorKindTemplate, err)
+	}
+	opt.LoadTemplate = getTemplate
+
+	generator, err := gounit.NewGenerator(opt, inputFile, outputFile)
+	if err == gounit.ErrFuncNotFound {
+		return errorResponse(gounit.ErrorKindFuncNotFound, err)
+	}
+
+	if err != nil {
+		return errorResponse(gounit.ErrorKindGenerator, err)
+	}
+
+	b := bytes.NewBuffer([]byte{})
+
+	if err := generator.Write(b); err != nil {
+		return errorResponse(gounit.ErrorKindWrite, err)
+	}
+
+	return gounit.Response{GeneratedCode: b.String()}
+}
+
+//writeDiff writes a unified diff between the original contents of the output
+//file and the generated code
+func writeDiff(w io.Writer, outputFile string, original string, generated []byte) error {
+	oldName := outputFile
+	if original == "" {
+		oldName = "/dev/null"
+	}
+
+	//nothing has been generated
+	if len(generated) == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(w, gounit.UnifiedDiff(oldName, outputFile, []byte(original), generated))
+	return err
+}
+
+//errorResponse returns a response that reports the error of the given kind
+func errorResponse(kind string, err error) gounit.Response {
+	return gounit.Response{Error: err.Error(), ErrorKind: kind}
 }
 
 //Set implements flag.Value interface
@@ -246,3 +600,176 @@ func (fl *FunctionsList) Set(value string) error {
 func (fl *FunctionsList) String() string {
 	return fmt.Sprintf("%s", []string(*fl))
 }
+
+//Set implements flag.Value interface
+func (pl *PatternsList) Set(value string) error {
+	chunks := strings.Split(value, ",")
+	for _, chunk := range chunks {
+		pattern := strings.TrimSpace(chunk)
+		if err := gounit.ValidatePattern(pattern); err != nil {
+			return err
+		}
+
+		*pl = append(*pl, pattern)
+	}
+
+	return nil
+}
+
+//String implements flag.Value interface
+func (pl *PatternsList) String() string {
+	return fmt.Sprintf("%s", []string(*pl))
+}
+
+//outputFileName returns a name of the test file for the given input file
+func outputFileName(inputFile string) string {
+	return trimGoExt(inputFile) + "_test.go"
+}
+
+//projectOptions applies the project configuration found for the input file
+//to the options, settings passed via command line flags take precedence
+func (gc *GenerateCommand) projectOptions(options gounit.Options, inputFile string) (gounit.Options, error) {
+	config, err := gounit.LoadProjectConfig(filepath.Dir(inputFile))
+	if err != nil {
+		return options, err
+	}
+
+	set := map[string]bool{}
+	if gc.fs != nil {
+		gc.fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	}
+
+	if !set["t"] && config.Template != "" {
+		options.TemplateName = config.Template
+	}
+
+	if !set["c"] && config.Comment != "" {
+		options.Comment = config.Comment
+	}
+
+	if !set["naming"] && config.Naming != "" {
+		options.Naming = config.Naming
+	}
+
+	if !set["x"] && config.Package != "" {
+		options.ExternalPackage = config.Package == gounit.PackageExternal
+	}
+
+	//examples are written to example_test.go unless -o flag is passed
+	if !set["o"] && config.Output != "" && options.Kind != gounit.KindExample {
+		options.OutputFile = config.OutputFile(inputFile)
+	}
+
+	return options, nil
+}
+
+//readCoverProfile reads the coverage profile written by go test -coverprofile
+func readCoverProfile(filename string) (*gounit.CoverProfile, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return gounit.ParseCoverProfile(f)
+}
+
+//kindFileName returns a name of the output file for the given input file and
+//kind of the generated functions, examples are written to example_test.go
+func kindFileName(inputFile string, kind gounit.Kind) string {
+	if kind == gounit.KindExample {
+		return filepath.Join(filepath.Dir(inputFile), "example_test.go")
+	}
+
+	return outputFileName(inputFile)
+}
+
+//trimGoExt returns the file name without .go extension
+func trimGoExt(filename string) string {
+	return strings.TrimSuffix(filename, ".go")
+}
+
+//expandPatterns returns a sorted list of non-test .go files matching
+//the given package patterns. Pattern can be a directory, a .go file
+//or a directory followed by "/..." to walk it recursively
+func expandPatterns(patterns []string) ([]string, error) {
+	var (
+		files []string
+		seen  = map[string]bool{}
+	)
+
+	add := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if pattern == "..." || strings.HasSuffix(pattern, "/...") {
+			root := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+			if root == "" {
+				root = "."
+			}
+
+			err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+
+				if fi.IsDir() {
+					if path != root && skipDir(fi.Name()) {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+
+				if isSourceFile(fi.Name()) {
+					add(path)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to walk %s: %v", root, err)
+			}
+
+			continue
+		}
+
+		fi, err := os.Stat(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid package pattern %q: %v", pattern, err)
+		}
+
+		if !fi.IsDir() {
+			add(pattern)
+			continue
+		}
+
+		infos, err := ioutil.ReadDir(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory %s: %v", pattern, err)
+		}
+
+		for _, info := range infos {
+			if !info.IsDir() && isSourceFile(info.Name()) {
+				add(filepath.Join(pattern, info.Name()))
+			}
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+//skipDir returns true for directories that are ignored by the go tool
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+//isSourceFile returns true if the file is a non-test .go file
+func isSourceFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
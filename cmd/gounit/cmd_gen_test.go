@@ -1,8 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/hexdigest/gounit"
 )
 
 func TestLinesNumbers_Set(t *testing.T) {
@@ -185,3 +195,479 @@ func TestFunctionsList_String(t *testing.T) {
 		})
 	}
 }
+
+func TestPatternsList_Set(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+
+		want    PatternsList
+		wantErr bool
+	}{
+		{
+			name:  "names and patterns",
+			value: "func1, Get*,Service.*,/^(Get|Set)/",
+			want:  PatternsList{"func1", "Get*", "Service.*", "/^(Get|Set)/"},
+		},
+		{
+			name:    "invalid glob",
+			value:   "func1,[",
+			wantErr: true,
+		},
+		{
+			name:    "invalid regexp",
+			value:   "/(/",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got PatternsList
+			err := got.Set(tt.value)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PatternsList.Set error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatternsList.Set got = %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputFileName(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+
+		want1 string
+	}{
+		{
+			name:      "go file",
+			inputFile: "pkg/file.go",
+			want1:     "pkg/file_test.go",
+		},
+		{
+			name:      "file without extension",
+			inputFile: "pkg/file",
+			want1:     "pkg/file_test.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := outputFileName(tt.inputFile)
+
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("outputFileName got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func Test_kindFileName(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+		kind      gounit.Kind
+
+		want1 string
+	}{
+		{
+			name:      "tests",
+			inputFile: "pkg/file.go",
+			want1:     "pkg/file_test.go",
+		},
+		{
+			name:      "benchmarks",
+			inputFile: "pkg/file.go",
+			kind:      gounit.KindBenchmark,
+			want1:     "pkg/file_test.go",
+		},
+		{
+			name:      "examples",
+			inputFile: "pkg/file.go",
+			kind:      gounit.KindExample,
+			want1:     "pkg/example_test.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := kindFileName(tt.inputFile, tt.kind)
+
+			if got1 != tt.want1 {
+				t.Errorf("kindFileName got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func Test_expandPatterns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a.go", "a_test.go", "sub/b.go", "sub/testdata/c.go", "vendor/d.go", "README.md"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte("package p"), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	type args struct {
+		patterns []string
+	}
+	tests := []struct {
+		name string
+		args func(t *testing.T) args
+
+		want1      []string
+		wantErr    bool
+		inspectErr func(err error, t *testing.T) //use for more precise error evaluation after test
+	}{
+		{
+			name: "directory",
+			args: func(*testing.T) args {
+				return args{patterns: []string{dir}}
+			},
+			want1: []string{filepath.Join(dir, "a.go")},
+		},
+		{
+			name: "recursive",
+			args: func(*testing.T) args {
+				return args{patterns: []string{dir + "/...", filepath.Join(dir, "a.go")}}
+			},
+			want1: []string{filepath.Join(dir, "a.go"), filepath.Join(dir, "sub/b.go")},
+		},
+		{
+			name: "missing directory",
+			args: func(*testing.T) args {
+				return args{patterns: []string{filepath.Join(dir, "missing")}}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tArgs := tt.args(t)
+
+			got1, err := expandPatterns(tArgs.patterns)
+
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("expandPatterns got1 = %v, want1: %v", got1, tt.want1)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandPatterns error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if tt.inspectErr != nil {
+				tt.inspectErr(err, t)
+			}
+		})
+	}
+}
+
+func TestGenerateCommand_processJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_json")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	request := func(inputFile, templateName string, lines string) string {
+		b, _ := json.Marshal(inputFile)
+		return `{"id":` + strconv.Itoa(len(inputFile)) + `,"inputFilePath":"` + filepath.Join(dir, "a.go") + `","outputFilePath":"` + filepath.Join(dir, "a_test.go") +
+			`","inputFile":` + string(b) + `,"templateName":"` + templateName + `","lines":` + lines + "}\n"
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		concurrency int
+
+		want1      []gounit.Response
+		wantErr    bool
+		inspectErr func(err error, t *testing.T) //use for more precise error evaluation after test
+	}{
+		{
+			name: "errors don't interrupt processing",
+			input: request("package a\n\nfunc A() {}", "missing", "[3]") +
+				request("package a\n\nfunc A() {}", "default", "[1]") +
+				request("packag a\n\nfunc A() {}", "default", "[3]") +
+				request("package a\n\nfunc A() {}", "default", `"3"`) +
+				request("package a\n\nfunc A() {}", "default", "[3]"),
+			want1: []gounit.Response{
+				{ErrorKind: gounit.ErrorKindTemplate},
+				{ErrorKind: gounit.ErrorKindFuncNotFound},
+				{ErrorKind: gounit.ErrorKindGenerator},
+				{ErrorKind: gounit.ErrorKindRequest},
+				{},
+			},
+		},
+		{
+			name:  "malformed JSON",
+			input: "{]" + request("package a\n\nfunc A() {}", "default", "[3]"),
+			want1: []gounit.Response{
+				{ErrorKind: gounit.ErrorKindRequest},
+			},
+			wantErr: true,
+		},
+		{
+			name: "concurrent processing",
+			input: request("package a\n\nfunc A() {}", "default", "[3]") +
+				request("package a\n\nfunc A()  {}", "default", "[3]") +
+				request("package a\n\nfunc A()   {}", "default", "[3]") +
+				request("package a\n\nfunc A()    {}", "default", "[3]"),
+			concurrency: 3,
+			want1:       []gounit.Response{{ID: []byte("22")}, {ID: []byte("23")}, {ID: []byte("24")}, {ID: []byte("25")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer([]byte{})
+
+			gc := &GenerateCommand{Options: gounit.Options{Concurrency: tt.concurrency}}
+			err := gc.processJSON(gc.Options, strings.NewReader(tt.input), out)
+
+			var got1 []gounit.Response
+			decoder := json.NewDecoder(out)
+			for decoder.More() {
+				var r gounit.Response
+				if err := decoder.Decode(&r); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				got1 = append(got1, r)
+			}
+
+			//responses of the concurrently processed requests may come in any order
+			if tt.concurrency > 1 {
+				sort.Slice(got1, func(i, j int) bool { return string(got1[i].ID) < string(got1[j].ID) })
+			}
+
+			if len(got1) != len(tt.want1) {
+				t.Fatalf("GenerateCommand.processJSON got1 = %v, want1: %v", got1, tt.want1)
+			}
+
+			for i, r := range got1 {
+				if tt.want1[i].ID != nil && string(r.ID) != string(tt.want1[i].ID) {
+					t.Errorf("GenerateCommand.processJSON response %d id = %s, want: %s", i, r.ID, tt.want1[i].ID)
+				}
+
+				if r.ErrorKind != tt.want1[i].ErrorKind || (r.ErrorKind != "") != (r.Error != "") {
+					t.Errorf("GenerateCommand.processJSON response %d = %+v, want kind: %q", i, r, tt.want1[i].ErrorKind)
+				}
+
+				if r.ErrorKind == "" && !strings.Contains(r.GeneratedCode, "func TestA(t *testing.T)") {
+					t.Errorf("GenerateCommand.processJSON response %d doesn't contain generated test: %s", i, r.GeneratedCode)
+				}
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateCommand.processJSON error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if tt.inspectErr != nil {
+				tt.inspectErr(err, t)
+			}
+		})
+	}
+}
+
+func TestGenerateCommand_processRequest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_json")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/a\n"), 0600); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	const (
+		src = "package a\n\nfunc A(s string) {}\n\nfunc B() {}\n\nfunc c() {}\n"
+
+		testSrc = "package a\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {\n\ttype args struct {\n\t\tn int\n\t}\n\n\ttests := []struct {\n\t\tname string\n\t\targs args\n\t}{}\n\n\tfor _, tt := range tests {\n\t\tt.Run(tt.name, func(t *testing.T) {\n\t\t\tA(tt.args.n)\n\t\t})\n\t}\n}\n"
+	)
+
+	request := gounit.Request{
+		InputFilePath:  filepath.Join(dir, "a.go"),
+		OutputFilePath: filepath.Join(dir, "a_test.go"),
+		InputFile:      src,
+		TemplateName:   "default",
+	}
+
+	tests := []struct {
+		name    string
+		options gounit.Options
+		lines   []int
+
+		want      []string
+		wantNot   []string
+		wantError string
+	}{
+		{
+			name: "all functions",
+			want: []string{"package a", "func TestB(", "func Test_c("},
+		},
+		{
+			name:    "lines of the request",
+			lines:   []int{5},
+			want:    []string{"func TestB("},
+			wantNot: []string{"func Test_c("},
+		},
+		{
+			name:    "external package",
+			options: gounit.Options{ExternalPackage: true},
+			want:    []string{"package a_test", "func TestB("},
+			wantNot: []string{"func Test_c("},
+		},
+		{
+			name:    "functions filter",
+			options: gounit.Options{Functions: []string{"c"}},
+			want:    []string{"func Test_c("},
+			wantNot: []string{"func TestB("},
+		},
+		{
+			name:    "exported filter",
+			options: gounit.Options{Exported: true},
+			want:    []string{"func TestB("},
+			wantNot: []string{"func Test_c("},
+		},
+		{
+			name:    "update",
+			options: gounit.Options{Update: true},
+			want:    []string{"A(tArgs.s)", "func TestB("},
+			wantNot: []string{"A(tt.args.n)"},
+		},
+		{
+			name:      "naming",
+			options:   gounit.Options{Naming: "{{.Name}}"},
+			wantError: gounit.ErrorKindGenerator,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc := &GenerateCommand{}
+
+			jo := request
+			jo.Lines = tt.lines
+			if !tt.options.ExternalPackage {
+				jo.OutputFile = testSrc
+			}
+
+			got1 := gc.processRequest(tt.options, jo)
+			if got1.ErrorKind != tt.wantError {
+				t.Fatalf("GenerateCommand.processRequest got1 = %+v, want error kind: %q", got1, tt.wantError)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(got1.GeneratedCode, want) {
+					t.Errorf("GenerateCommand.processRequest generated code doesn't contain %q: %s", want, got1.GeneratedCode)
+				}
+			}
+
+			for _, want := range tt.wantNot {
+				if strings.Contains(got1.GeneratedCode, want) {
+					t.Errorf("GenerateCommand.processRequest generated code contains %q: %s", want, got1.GeneratedCode)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateCommand_projectOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_config")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		".gounit.json": `{"template": "./config.tmpl", "comment": "config comment", "output": "{name}_gen_test.go", "package": "external", "naming": "Test{{.Type}}{{.Name}}"}`,
+		"config.tmpl":  testTemplate,
+		"go.mod":       "module example.com/a\n",
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	inputFile := filepath.Join(dir, "a.go")
+
+	tests := []struct {
+		name string
+		args []string
+
+		want1 gounit.Options
+	}{
+		{
+			name: "config",
+			want1: gounit.Options{
+				TemplateName:    filepath.Join(dir, "config.tmpl"),
+				Comment:         "config comment",
+				OutputFile:      filepath.Join(dir, "a_gen_test.go"),
+				ExternalPackage: true,
+				Naming:          "Test{{.Type}}{{.Name}}",
+			},
+		},
+		{
+			name: "flags take precedence",
+			args: []string{"-t", "default", "-c", "flag comment", "-o", "a_test.go", "-x=false", "-naming", "Test{{.Name}}"},
+			want1: gounit.Options{
+				TemplateName: "default",
+				Comment:      "flag comment",
+				OutputFile:   "a_test.go",
+				Naming:       "Test{{.Name}}",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc := &GenerateCommand{}
+			if err := gc.FlagSet().Parse(tt.args); err != nil {
+				t.Fatalf("failed to parse flags: %v", err)
+			}
+
+			got1, err := gc.projectOptions(gc.Options, inputFile)
+			if err != nil {
+				t.Fatalf("GenerateCommand.projectOptions: %v", err)
+			}
+
+			got1.All, got1.Concurrency = false, 0
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("GenerateCommand.projectOptions got1 = %+v, want1: %+v", got1, tt.want1)
+			}
+
+			//the same precedence applies to the JSON requests
+			response := gc.processRequest(gc.Options, gounit.Request{InputFilePath: inputFile, InputFile: "package a\n\nfunc A() {}\n"})
+			if response.ErrorKind != "" {
+				t.Fatalf("GenerateCommand.processRequest: %v", response.Error)
+			}
+
+			if got, want := strings.HasPrefix(response.GeneratedCode, "package a_test"), tt.want1.ExternalPackage; got != want {
+				t.Errorf("GenerateCommand.processRequest generated code of the external package: %t, want: %t\n%s", got, want, response.GeneratedCode)
+			}
+
+			if !strings.Contains(response.GeneratedCode, tt.want1.Comment) {
+				t.Errorf("GenerateCommand.processRequest generated code doesn't contain %q: %s", tt.want1.Comment, response.GeneratedCode)
+			}
+		})
+	}
+}
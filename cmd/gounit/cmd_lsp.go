@@ -0,0 +1,666 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/hexdigest/gounit"
+)
+
+//LSP error codes
+const (
+	lspParseError     = -32700
+	lspMethodNotFound = -32601
+	lspInvalidParams  = -32602
+	lspInternalError  = -32603
+)
+
+//code action kind of the generated code actions
+const lspCodeActionKind = "source.gounit"
+
+//LSPCommand implements Command interface
+type LSPCommand struct {
+	fs           *flag.FlagSet
+	templateName string
+	typeCheck    bool
+}
+
+//Description implements Command interface
+func (lc *LSPCommand) Description() string {
+	return "run language server for editor integration"
+}
+
+func (lc *LSPCommand) Usage() string {
+	return `usage: gounit lsp [-t template name] [-types]
+
+Runs a Language Server Protocol server that communicates with an editor over stdin/stdout.
+The server offers "Generate test", "Generate tests for file" and "Generate missing tests"
+code actions for the Go source files. Code actions return workspace edits that create or
+update the corresponding _test.go files.
+`
+}
+
+func (lc *LSPCommand) FlagSet() *flag.FlagSet {
+	if lc.fs == nil {
+		lc.fs = &flag.FlagSet{}
+		lc.fs.StringVar(&lc.templateName, "t", "", "name of the template to use for the code generation (optional)")
+		lc.fs.BoolVar(&lc.typeCheck, "types", false, "load the source package with the type information")
+	}
+
+	return lc.fs
+}
+
+func (lc *LSPCommand) Run(args []string, stdout, stderr io.Writer) error {
+	if err := lc.FlagSet().Parse(args); err != nil {
+		return gounit.CommandLineError(err.Error())
+	}
+
+	tmpl, err := getTemplate(lc.templateName)
+	if err != nil {
+		return err
+	}
+
+	s := newLSPServer(gounit.Options{Template: tmpl, TypeCheck: lc.typeCheck, LoadTemplate: getTemplate}, stdout)
+
+	return s.serve(os.Stdin)
+}
+
+//lspMessage is a JSON-RPC request or notification sent by the client
+type lspMessage struct {
+	ID     *json.RawMessage `json:"id"`
+	Method string           `json:"method"`
+	Params json.RawMessage  `json:"params"`
+}
+
+//lspError is a JSON-RPC error object
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspTextDocument struct {
+	URI     string `json:"uri"`
+	Version *int   `json:"version,omitempty"`
+	Text    string `json:"text,omitempty"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspCreateFile struct {
+	Kind string `json:"kind"`
+	URI  string `json:"uri"`
+}
+
+type lspTextDocumentEdit struct {
+	TextDocument lspVersionedDocument `json:"textDocument"`
+	Edits        []lspTextEdit        `json:"edits"`
+}
+
+//lspVersionedDocument is an OptionalVersionedTextDocumentIdentifier,
+//the null version means the version of the file on disk
+type lspVersionedDocument struct {
+	URI     string `json:"uri"`
+	Version *int   `json:"version"`
+}
+
+type lspWorkspaceEdit struct {
+	DocumentChanges []interface{} `json:"documentChanges"`
+}
+
+//lspCodeAction is a code action, the edit of the action is computed
+//on the codeAction/resolve request if the action has data
+type lspCodeAction struct {
+	Title string            `json:"title"`
+	Kind  string            `json:"kind"`
+	Edit  *lspWorkspaceEdit `json:"edit,omitempty"`
+	Data  *lspActionData    `json:"data,omitempty"`
+}
+
+//lspActionData identifies the document of the code action to resolve
+type lspActionData struct {
+	URI string `json:"uri"`
+}
+
+type lspInitializeParams struct {
+	Capabilities struct {
+		TextDocument struct {
+			CodeAction struct {
+				ResolveSupport struct {
+					Properties []string `json:"properties"`
+				} `json:"resolveSupport"`
+			} `json:"codeAction"`
+		} `json:"textDocument"`
+	} `json:"capabilities"`
+}
+
+type lspCodeActionParams struct {
+	TextDocument lspTextDocument `json:"textDocument"`
+	Range        lspRange        `json:"range"`
+	Context      struct {
+		Only []string `json:"only"`
+	} `json:"context"`
+}
+
+type lspDidOpenParams struct {
+	TextDocument lspTextDocument `json:"textDocument"`
+}
+
+type lspDidChangeParams struct {
+	TextDocument   lspTextDocument `json:"textDocument"`
+	ContentChanges []struct {
+		Range *lspRange `json:"range"`
+		Text  string    `json:"text"`
+	} `json:"contentChanges"`
+}
+
+//lspDocument is a document opened in the editor
+type lspDocument struct {
+	text    string
+	version int
+}
+
+//lspServer serves code actions that generate tests
+type lspServer struct {
+	opt       gounit.Options
+	w         io.Writer
+	documents map[string]lspDocument
+
+	//resolveEdit is true if the client can resolve the edits of the code actions lazily
+	resolveEdit bool
+}
+
+func newLSPServer(opt gounit.Options, w io.Writer) *lspServer {
+	return &lspServer{
+		opt:       opt,
+		w:         w,
+		documents: map[string]lspDocument{},
+	}
+}
+
+//serve reads messages from r until the exit notification or EOF
+func (s *lspServer) serve(r io.Reader) error {
+	br := bufio.NewReader(r)
+
+	for {
+		b, err := readLSPMessage(br)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		//the id of the malformed message is unknown so the response has the null id
+		var msg lspMessage
+		if err := json.Unmarshal(b, &msg); err != nil {
+			response := map[string]interface{}{"jsonrpc": "2.0", "id": nil, "error": &lspError{Code: lspParseError, Message: err.Error()}}
+			if err := writeLSPMessage(s.w, response); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if msg.Method == "exit" {
+			return nil
+		}
+
+		result, lspErr := s.handle(msg)
+
+		//notifications don't have responses
+		if msg.ID == nil {
+			continue
+		}
+
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": msg.ID}
+		if lspErr != nil {
+			response["error"] = lspErr
+		} else {
+			response["result"] = result
+		}
+
+		if err := writeLSPMessage(s.w, response); err != nil {
+			return err
+		}
+	}
+}
+
+//handle handles the message and returns the result of the request
+func (s *lspServer) handle(msg lspMessage) (interface{}, *lspError) {
+	switch msg.Method {
+	case "initialize":
+		var params lspInitializeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &lspError{Code: lspInvalidParams, Message: err.Error()}
+		}
+
+		for _, property := range params.Capabilities.TextDocument.CodeAction.ResolveSupport.Properties {
+			s.resolveEdit = s.resolveEdit || property == "edit"
+		}
+
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": 1, //full document sync
+				"codeActionProvider": map[string]interface{}{
+					"codeActionKinds": []string{lspCodeActionKind},
+					"resolveProvider": true,
+				},
+			},
+			"serverInfo": map[string]string{"name": "gounit"},
+		}, nil
+	case "shutdown":
+		return nil, nil
+	case "textDocument/didOpen":
+		var params lspDidOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &lspError{Code: lspInvalidParams, Message: err.Error()}
+		}
+
+		s.documents[params.TextDocument.URI] = lspDocument{text: params.TextDocument.Text, version: versionOf(params.TextDocument)}
+	case "textDocument/didChange":
+		var params lspDidChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &lspError{Code: lspInvalidParams, Message: err.Error()}
+		}
+
+		//server requests full document sync so the last change contains the whole document
+		if n := len(params.ContentChanges); n > 0 && params.ContentChanges[n-1].Range == nil {
+			s.documents[params.TextDocument.URI] = lspDocument{text: params.ContentChanges[n-1].Text, version: versionOf(params.TextDocument)}
+		}
+	case "textDocument/didClose":
+		var params lspDidOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &lspError{Code: lspInvalidParams, Message: err.Error()}
+		}
+
+		delete(s.documents, params.TextDocument.URI)
+	case "textDocument/codeAction":
+		var params lspCodeActionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &lspError{Code: lspInvalidParams, Message: err.Error()}
+		}
+
+		actions, err := s.codeActions(params)
+		if err != nil {
+			return nil, &lspError{Code: lspInternalError, Message: err.Error()}
+		}
+
+		return actions, nil
+	case "codeAction/resolve":
+		var action lspCodeAction
+		if err := json.Unmarshal(msg.Params, &action); err != nil {
+			return nil, &lspError{Code: lspInvalidParams, Message: err.Error()}
+		}
+
+		if err := s.resolve(&action); err != nil {
+			return nil, &lspError{Code: lspInternalError, Message: err.Error()}
+		}
+
+		return action, nil
+	case "initialized", "textDocument/didSave", "$/cancelRequest", "$/setTrace":
+	default:
+		if msg.ID != nil {
+			return nil, &lspError{Code: lspMethodNotFound, Message: "method not found: " + msg.Method}
+		}
+	}
+
+	return nil, nil
+}
+
+//codeActions returns code actions available for the given range of the document.
+//Actions for the document are computed right away while the edit of the action
+//for the whole package is resolved on demand if the client supports it
+func (s *lspServer) codeActions(params lspCodeActionParams) ([]lspCodeAction, error) {
+	actions := []lspCodeAction{}
+
+	if !kindRequested(params.Context.Only) {
+		return actions, nil
+	}
+
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil || !isSourceFile(path) {
+		return actions, nil
+	}
+
+	src, err := s.read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if line := enclosingFuncLine(src, params.Range.Start.Line+1); line > 0 {
+		opt := s.opt
+		opt.Lines = []int{line}
+
+		actions = s.addAction(actions, "Generate test", s.edit([]string{path}, opt))
+	}
+
+	opt := s.opt
+	opt.All = true
+
+	actions = s.addAction(actions, "Generate tests for file", s.edit([]string{path}, opt))
+
+	missing := lspCodeAction{Title: "Generate missing tests", Kind: lspCodeActionKind, Data: &lspActionData{URI: params.TextDocument.URI}}
+	if s.resolveEdit {
+		files, err := expandPatterns([]string{filepath.Dir(path)})
+		if err != nil {
+			return nil, err
+		}
+
+		if !s.missingTests(files, opt) {
+			return actions, nil
+		}
+
+		return append(actions, missing), nil
+	}
+
+	if err := s.resolve(&missing); err != nil {
+		return nil, err
+	}
+
+	return s.addAction(actions, missing.Title, *missing.Edit), nil
+}
+
+//resolve computes the edit of the "Generate missing tests" action
+func (s *lspServer) resolve(action *lspCodeAction) error {
+	if action.Data == nil {
+		return nil
+	}
+
+	path, err := uriToPath(action.Data.URI)
+	if err != nil {
+		return err
+	}
+
+	files, err := expandPatterns([]string{filepath.Dir(path)})
+	if err != nil {
+		return err
+	}
+
+	opt := s.opt
+	opt.All = true
+
+	edit := s.edit(files, opt)
+	action.Edit = &edit
+	action.Data = nil
+
+	return nil
+}
+
+//addAction appends the code action to actions unless its edit is empty
+func (s *lspServer) addAction(actions []lspCodeAction, title string, edit lspWorkspaceEdit) []lspCodeAction {
+	if len(edit.DocumentChanges) == 0 {
+		return actions
+	}
+
+	return append(actions, lspCodeAction{Title: title, Kind: lspCodeActionKind, Edit: &edit})
+}
+
+//edit returns the workspace edit that generates tests for the files, files
+//that can't be processed, i.e. the ones with syntax errors, are skipped
+func (s *lspServer) edit(files []string, opt gounit.Options) lspWorkspaceEdit {
+	edit := lspWorkspaceEdit{DocumentChanges: []interface{}{}}
+
+	for _, file := range files {
+		opt.InputFile = file
+		opt.OutputFile = outputFileName(file)
+
+		changes, err := s.generate(opt)
+		if err != nil {
+			continue
+		}
+
+		edit.DocumentChanges = append(edit.DocumentChanges, changes...)
+	}
+
+	return edit
+}
+
+//missingTests returns true if some function of the files doesn't have a test,
+//it's a cheap check that doesn't render the tests
+func (s *lspServer) missingTests(files []string, opt gounit.Options) bool {
+	for _, file := range files {
+		opt.InputFile = file
+		opt.OutputFile = outputFileName(file)
+
+		if generator, _, _, err := s.newGenerator(opt); err == nil && generator != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+//newGenerator returns the generator for the input file along with the contents of
+//the test file and the flag telling if the test file exists.
+//Generator is nil if all functions of the file are already tested
+func (s *lspServer) newGenerator(opt gounit.Options) (*gounit.Generator, string, bool, error) {
+	src, err := s.read(opt.InputFile)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	testSrc, err := s.read(opt.OutputFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, "", false, gounit.ErrFailedToOpenOutFile.Format(err)
+	}
+
+	exists := err == nil
+
+	var testReader io.Reader
+	if exists {
+		testReader = strings.NewReader(testSrc)
+	}
+
+	generator, err := gounit.NewGenerator(opt, strings.NewReader(src), testReader)
+	if err == gounit.ErrFuncNotFound {
+		return nil, testSrc, exists, nil
+	}
+
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	if len(generator.Funcs()) == 0 {
+		return nil, testSrc, exists, nil
+	}
+
+	return generator, testSrc, exists, nil
+}
+
+//generate returns document changes that create or update the test file
+func (s *lspServer) generate(opt gounit.Options) ([]interface{}, error) {
+	generator, testSrc, exists, err := s.newGenerator(opt)
+	if err != nil || generator == nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := generator.Write(buf); err != nil {
+		return nil, err
+	}
+
+	var (
+		uri     = pathToURI(opt.OutputFile)
+		doc     = lspVersionedDocument{URI: uri}
+		changes []interface{}
+	)
+
+	if d, ok := s.documents[uri]; ok {
+		doc.Version = &d.version
+	}
+
+	if !exists {
+		changes = append(changes, lspCreateFile{Kind: "create", URI: uri})
+	}
+
+	return append(changes, lspTextDocumentEdit{
+		TextDocument: doc,
+		Edits:        []lspTextEdit{{Range: lspRange{End: endPosition(testSrc)}, NewText: buf.String()}},
+	}), nil
+}
+
+//read returns contents of the file opened in the editor
+//or reads the file from disk if it's not opened
+func (s *lspServer) read(path string) (string, error) {
+	if d, ok := s.documents[pathToURI(path)]; ok {
+		return d.text, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	return string(b), err
+}
+
+//enclosingFuncLine returns the line of the declaration of the function
+//that encloses the given line or 0 if there is no such function
+func enclosingFuncLine(src string, line int) int {
+	fs := token.NewFileSet()
+
+	//the file may be incomplete while it's being edited
+	file, _ := parser.ParseFile(fs, "", src, 0)
+	if file == nil {
+		return 0
+	}
+
+	for _, decl := range file.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok {
+			start, end := fs.Position(fd.Pos()).Line, fs.Position(fd.End()).Line
+			if line >= start && line <= end {
+				return start
+			}
+		}
+	}
+
+	return 0
+}
+
+//kindRequested returns true if the client requested code actions of the gounit kind
+func kindRequested(only []string) bool {
+	if len(only) == 0 {
+		return true
+	}
+
+	for _, kind := range only {
+		if kind == lspCodeActionKind || strings.HasPrefix(lspCodeActionKind, kind+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+//endPosition returns the position of the end of the text,
+//characters are counted in UTF-16 code units
+func endPosition(text string) lspPosition {
+	lines := strings.Split(text, "\n")
+	last := lines[len(lines)-1]
+
+	return lspPosition{Line: len(lines) - 1, Character: len(utf16.Encode([]rune(last)))}
+}
+
+//versionOf returns the version of the document or 0 if it's not specified
+func versionOf(doc lspTextDocument) int {
+	if doc.Version == nil {
+		return 0
+	}
+
+	return *doc.Version
+}
+
+//uriToPath converts file:// URI to the file path
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI scheme: %s", u.Scheme)
+	}
+
+	return filepath.FromSlash(u.Path), nil
+}
+
+//pathToURI converts the file path to file:// URI
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+//readLSPMessage reads the message content prefixed by the Content-Length header
+func readLSPMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length == -1 {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to read message header: %v", err)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+
+		if value := strings.TrimPrefix(line, "Content-Length:"); value != line {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("invalid Content-Length header: %v", err)
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	b := make([]byte, length)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("failed to read message content: %v", err)
+	}
+
+	return b, nil
+}
+
+//writeLSPMessage writes the JSON-encoded message prefixed by the Content-Length header
+func writeLSPMessage(w io.Writer, msg interface{}) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	return err
+}
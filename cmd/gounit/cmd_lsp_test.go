@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hexdigest/gounit"
+)
+
+const lspSource = `package lsp
+
+func One() int { return 1 }
+
+func Two() int {
+	return 2
+}
+`
+
+func Test_lspServer_serve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_lsp")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "lsp.go")
+	if err := ioutil.WriteFile(file, []byte(lspSource), 0600); err != nil {
+		t.Fatalf("failed to write source file: %v", err)
+	}
+
+	uri := pathToURI(file)
+
+	requests := bytes.NewBuffer([]byte{})
+	for _, msg := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"` + uri + `","version":1,"text":` + strings.Replace(jsonString(t, lspSource), "return 2", "return 3", 1) + `}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"` + uri + `"},"range":{"start":{"line":5,"character":0},"end":{"line":5,"character":0}},"context":{}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"` + uri + `"},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"context":{"only":["quickfix"]}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"unknown","params":{}}`,
+		`{"jsonrpc":"2.0","id":5,"method":`,
+		`{"jsonrpc":"2.0","id":6,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	} {
+		fmt.Fprintf(requests, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+
+	responses := bytes.NewBuffer([]byte{})
+	s := newLSPServer(gounit.Options{Template: testTemplate}, responses)
+
+	if err := s.serve(requests); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+
+	var (
+		r    = bufio.NewReader(responses)
+		got1 []map[string]json.RawMessage
+	)
+
+	for {
+		b, err := readLSPMessage(r)
+		if err != nil {
+			break
+		}
+
+		var response map[string]json.RawMessage
+		if err := json.Unmarshal(b, &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		got1 = append(got1, response)
+	}
+
+	if len(got1) != 6 {
+		t.Fatalf("expected 6 responses, got: %d", len(got1))
+	}
+
+	var actions []lspCodeAction
+	if err := json.Unmarshal(got1[1]["result"], &actions); err != nil {
+		t.Fatalf("failed to decode code actions: %v", err)
+	}
+
+	var titles []string
+	for _, a := range actions {
+		titles = append(titles, a.Title)
+	}
+
+	if want := "Generate test,Generate tests for file,Generate missing tests"; strings.Join(titles, ",") != want {
+		t.Fatalf("unexpected code actions: %v", titles)
+	}
+
+	edit, _ := json.Marshal(actions[0].Edit)
+	for _, want := range []string{`"kind":"create"`, "lsp_test.go", "func TestTwo(t *testing.T)"} {
+		if !strings.Contains(string(edit), want) {
+			t.Errorf("Generate test edit doesn't contain %q: %s", want, edit)
+		}
+	}
+
+	if strings.Contains(string(edit), "TestOne") {
+		t.Errorf("Generate test edit contains test for another function: %s", edit)
+	}
+
+	if string(got1[2]["result"]) != "[]" {
+		t.Errorf("unexpected code actions of the other kind: %s", got1[2]["result"])
+	}
+
+	if _, ok := got1[3]["error"]; !ok {
+		t.Errorf("expected error for the unknown method: %v", got1[3])
+	}
+
+	if string(got1[4]["id"]) != "null" || !strings.Contains(string(got1[4]["error"]), `"code":-32700`) {
+		t.Errorf("expected parse error for the malformed message: %v", got1[4])
+	}
+
+	if string(got1[5]["result"]) != "null" {
+		t.Errorf("unexpected shutdown result: %s", got1[5]["result"])
+	}
+}
+
+func Test_lspServer_codeActions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_lsp")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "lsp.go")
+	for name, src := range map[string]string{file: lspSource, filepath.Join(dir, "broken.go"): "func Broken() {}\n"} {
+		if err := ioutil.WriteFile(name, []byte(src), 0600); err != nil {
+			t.Fatalf("failed to write source file: %v", err)
+		}
+	}
+
+	codeAction := json.RawMessage(`{"textDocument":{"uri":"` + pathToURI(file) + `"},"range":{"start":{"line":2,"character":0},"end":{"line":2,"character":0}},"context":{}}`)
+
+	tests := []struct {
+		name         string
+		capabilities string
+
+		wantResolve bool
+	}{
+		{name: "eager edit", capabilities: `{}`},
+		{name: "resolved edit", capabilities: `{"textDocument":{"codeAction":{"resolveSupport":{"properties":["edit"]}}}}`, wantResolve: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLSPServer(gounit.Options{Template: testTemplate}, ioutil.Discard)
+
+			if _, err := s.handle(lspMessage{Method: "initialize", Params: json.RawMessage(`{"capabilities":` + tt.capabilities + `}`)}); err != nil {
+				t.Fatalf("initialize: %v", err.Message)
+			}
+
+			result, lspErr := s.handle(lspMessage{Method: "textDocument/codeAction", Params: codeAction})
+			if lspErr != nil {
+				t.Fatalf("codeAction: %v", lspErr.Message)
+			}
+
+			actions := result.([]lspCodeAction)
+			if len(actions) != 3 {
+				t.Fatalf("expected 3 code actions, got: %d", len(actions))
+			}
+
+			missing := actions[2]
+			if (missing.Data != nil) != tt.wantResolve || (missing.Edit == nil) != tt.wantResolve {
+				t.Fatalf("unexpected %q action: %+v", missing.Title, missing)
+			}
+
+			if tt.wantResolve {
+				b, _ := json.Marshal(missing)
+
+				result, lspErr := s.handle(lspMessage{Method: "codeAction/resolve", Params: b})
+				if lspErr != nil {
+					t.Fatalf("codeAction/resolve: %v", lspErr.Message)
+				}
+
+				missing = result.(lspCodeAction)
+			}
+
+			edit, _ := json.Marshal(missing.Edit)
+			for _, want := range []string{"lsp_test.go", "func TestOne(t *testing.T)", "func TestTwo(t *testing.T)"} {
+				if !strings.Contains(string(edit), want) {
+					t.Errorf("%q edit doesn't contain %q: %s", missing.Title, want, edit)
+				}
+			}
+
+			if strings.Contains(string(edit), "broken_test.go") {
+				t.Errorf("%q edit contains test file of the broken file: %s", missing.Title, edit)
+			}
+		})
+	}
+}
+
+func Test_lspServer_codeActions_nothingMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_lsp")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "lsp.go")
+	testSource := "package lsp\n\nimport \"testing\"\n\nfunc TestOne(t *testing.T) {}\n\nfunc TestTwo(t *testing.T) {}\n"
+	for name, src := range map[string]string{file: lspSource, filepath.Join(dir, "lsp_test.go"): testSource} {
+		if err := ioutil.WriteFile(name, []byte(src), 0600); err != nil {
+			t.Fatalf("failed to write source file: %v", err)
+		}
+	}
+
+	codeAction := json.RawMessage(`{"textDocument":{"uri":"` + pathToURI(file) + `"},"range":{"start":{"line":2,"character":0},"end":{"line":2,"character":0}},"context":{}}`)
+
+	tests := []struct {
+		name         string
+		capabilities string
+	}{
+		{name: "eager edit", capabilities: `{}`},
+		{name: "resolved edit", capabilities: `{"textDocument":{"codeAction":{"resolveSupport":{"properties":["edit"]}}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLSPServer(gounit.Options{Template: testTemplate}, ioutil.Discard)
+
+			if _, err := s.handle(lspMessage{Method: "initialize", Params: json.RawMessage(`{"capabilities":` + tt.capabilities + `}`)}); err != nil {
+				t.Fatalf("initialize: %v", err.Message)
+			}
+
+			result, lspErr := s.handle(lspMessage{Method: "textDocument/codeAction", Params: codeAction})
+			if lspErr != nil {
+				t.Fatalf("codeAction: %v", lspErr.Message)
+			}
+
+			if actions := result.([]lspCodeAction); len(actions) != 0 {
+				t.Errorf("expected no code actions when all functions are tested, got: %+v", actions)
+			}
+		})
+	}
+}
+
+func Test_endPosition(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+
+		want1 lspPosition
+	}{
+		{name: "empty", text: "", want1: lspPosition{}},
+		{name: "trailing new line", text: "a\nb\n", want1: lspPosition{Line: 2}},
+		{name: "surrogate pair", text: "a\n𝄞b", want1: lspPosition{Line: 1, Character: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got1 := endPosition(tt.text); got1 != tt.want1 {
+				t.Errorf("endPosition got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func jsonString(t *testing.T, s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("failed to encode string: %v", err)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hexdigest/gounit"
+)
+
+//MockCommand implements Command interface
+type MockCommand struct {
+	Options gounit.MockOptions
+	fs      *flag.FlagSet
+	ifaces  FunctionsList
+	stdout  bool
+}
+
+//Description implements Command interface
+func (mc *MockCommand) Description() string {
+	return "generate fake implementations of interfaces"
+}
+
+func (mc *MockCommand) Usage() string {
+	return `usage: gounit mock [-o output file] [-f interfaces] [-stdout] file.go | package directory
+
+Mocks are hand-rolled fakes with call recording and per-method funcs returning results, i.e.
+for the method Do of the interface Service the fake ServiceMock has DoFunc and DoCalls fields.
+By default mocks of the interfaces declared in file.go are written to file_mock_test.go and
+mocks of all interfaces of the package are written to mocks_test.go in the package directory.
+`
+}
+
+func (mc *MockCommand) FlagSet() *flag.FlagSet {
+	o := &mc.Options
+
+	if mc.fs == nil {
+		mc.fs = &flag.FlagSet{}
+		mc.fs.StringVar(&o.OutputFile, "o", "", "output file name (optional)")
+		mc.fs.BoolVar(&mc.stdout, "stdout", false, "use stdout rather than writing to the output file")
+		mc.fs.Var(&mc.ifaces, "f", "comma-separated names of the interfaces to mock")
+	}
+
+	return mc.fs
+}
+
+func (mc *MockCommand) Run(args []string, stdout, stderr io.Writer) error {
+	if err := mc.FlagSet().Parse(args); err != nil {
+		return gounit.CommandLineError(err.Error())
+	}
+
+	if mc.FlagSet().NArg() != 1 {
+		return gounit.CommandLineError("expected a single input file or a package directory")
+	}
+
+	options := mc.Options
+	options.Input = mc.FlagSet().Arg(0)
+	options.Interfaces = []string(mc.ifaces)
+
+	if options.OutputFile == "" {
+		if fi, err := os.Stat(options.Input); err == nil && fi.IsDir() {
+			options.OutputFile = filepath.Join(options.Input, "mocks_test.go")
+		} else {
+			options.OutputFile = outputFileName(trimGoExt(options.Input) + "_mock.go")
+		}
+	}
+
+	generator, err := gounit.NewMockGenerator(options)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := generator.Write(buf); err != nil {
+		return err
+	}
+
+	if mc.stdout {
+		_, err = stdout.Write(buf.Bytes())
+		return err
+	}
+
+	if err := ioutil.WriteFile(options.OutputFile, buf.Bytes(), 0600); err != nil {
+		return gounit.ErrWriteTest.Format(err)
+	}
+
+	for _, name := range generator.Interfaces() {
+		fmt.Fprintf(stdout, "%s: %sMock generated\n", options.OutputFile, name)
+	}
+
+	return nil
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -10,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/hexdigest/gounit"
 	"github.com/shibukawa/configdir"
@@ -42,11 +42,20 @@ func (tc *TemplateCommand) Usage() string {
 Subcommands usage examples:
 
 	gounit template add <file>
-		install a template, file name is used as a template name
+		install a template, the name from the front matter of the template
+		or the file name is used as a template name
+
+	gounit template check <file>
+		render a template for the corpus of tricky signatures (methods, variadic
+		and generic functions, multiple results, etc.) and check that the generated
+		code parses and type-checks
 
 	gounit template list
 		show all installed templates
 
+	gounit template show <template>
+		show metadata and the source of the template
+
 	gounit template use <template>
 		use selected template by default
 
@@ -69,7 +78,12 @@ func (tc *TemplateCommand) Run(args []string, stdout, stderr io.Writer) error {
 		if len(args) < 2 {
 			return gounit.CommandLineError("missing file name")
 		}
-		return installTemplate(args[1])
+		return installTemplate(args[1], stderr)
+	case "check":
+		if len(args) < 2 {
+			return gounit.CommandLineError("missing file name")
+		}
+		return checkTemplate(args[1], stdout, true)
 	case "list":
 		return listTemplates()
 	case "use":
@@ -77,6 +91,11 @@ func (tc *TemplateCommand) Run(args []string, stdout, stderr io.Writer) error {
 			return gounit.CommandLineError("missing template name")
 		}
 		return useTemplate(args[1])
+	case "show":
+		if len(args) < 2 {
+			return gounit.CommandLineError("missing template name")
+		}
+		return showTemplate(args[1], stdout)
 	case "remove":
 		if len(args) < 2 {
 			return gounit.CommandLineError("missing template name")
@@ -87,18 +106,36 @@ func (tc *TemplateCommand) Run(args []string, stdout, stderr io.Writer) error {
 	return gounit.CommandLineError(fmt.Sprintf("invalid subcommand %q", args[0]))
 }
 
-func installTemplate(filename string) error {
+func installTemplate(filename string, w io.Writer) error {
 	if filename == "" {
 		return gounit.CommandLineError("missing file name")
 	}
 
+	if err := checkTemplate(filename, w, false); err != nil {
+		return err
+	}
+
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	meta, _, err := gounit.ParseTemplate(string(contents))
+	if err != nil {
+		return err
+	}
+
 	_, templateName := filepath.Split(filename)
+	if meta.Name != "" {
+		templateName = meta.Name
+	}
+
 	if templateName == defaultTemplateName {
 		return gounit.CommandLineError("can't rewrite default template")
 	}
 
-	if err := checkTemplate(filename); err != nil {
-		return err
+	if strings.ContainsAny(templateName, `/\`) {
+		return gounit.CommandLineError("invalid template name: " + templateName)
 	}
 
 	from, err := os.Open(filename)
@@ -133,19 +170,59 @@ func listTemplates() error {
 
 	fmt.Printf("\ngounit templates installed\n\n")
 
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	for _, name := range names {
-		format := "      %s\n"
+		format := "      %s\t%s\t%s\n"
 		if name == templateName {
-			format = "    * %s\n"
+			format = "    * %s\t%s\t%s\n"
 		}
-		fmt.Printf(format, name)
+
+		//broken templates are still listed so they can be removed
+		meta, _ := getTemplateMeta(name)
+		fmt.Fprintf(w, format, name, meta.Version, meta.Description)
 	}
+	w.Flush()
 
 	fmt.Println()
 
 	return nil
 }
 
+//showTemplate prints metadata and the source of the template
+func showTemplate(name string, w io.Writer) error {
+	if err := templateExists(name); err != nil {
+		return err
+	}
+
+	contents, err := getTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	meta, body, err := gounit.ParseTemplate(contents)
+	if err != nil {
+		return err
+	}
+
+	if meta.Name == "" {
+		meta.Name = name
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n\n%s\n", meta, strings.TrimSpace(body))
+	return err
+}
+
+//getTemplateMeta returns metadata of the installed template
+func getTemplateMeta(name string) (gounit.TemplateMeta, error) {
+	contents, err := getTemplate(name)
+	if err != nil {
+		return gounit.TemplateMeta{}, err
+	}
+
+	meta, _, err := gounit.ParseTemplate(contents)
+	return meta, err
+}
+
 func getTemplate(name string) (string, error) {
 	var err error
 
@@ -159,7 +236,14 @@ func getTemplate(name string) (string, error) {
 		return testTemplate, nil
 	}
 
-	b, err := ioutil.ReadFile(filepath.Join(conf.Path, "templates", name))
+	path := filepath.Join(conf.Path, "templates", name)
+
+	//template file set in the project configuration
+	if filepath.IsAbs(name) {
+		path = name
+	}
+
+	b, err := ioutil.ReadFile(path)
 	if err != nil {
 		return "", err
 	}
@@ -283,47 +367,55 @@ func writeConfig(c Config) error {
 	return nil
 }
 
-//checkTemplate executes template against a simple func and
-//verifies that produced result is a syntactically correct .go code
-func checkTemplate(filename string) error {
-	src := strings.NewReader(`
-		package funcs
-
-		func function() int {
-			return 0
-		}`)
-
-	f, err := os.Open(filename)
+//checkTemplate executes the template against the corpus of tricky signatures
+//and verifies that the generated code parses and type-checks. Failed cases are
+//always printed while the passed and the skipped ones only in verbose mode
+func checkTemplate(filename string, w io.Writer, verbose bool) error {
+	contents, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return err
 	}
 
-	contents, err := ioutil.ReadAll(f)
+	checks, err := gounit.CheckTemplate(string(contents))
 	if err != nil {
 		return err
 	}
 
-	opt := gounit.Options{
-		Template: string(contents),
-		All:      true,
-	}
+	failed := 0
+	for _, c := range checks {
+		if c.Status == gounit.CheckFailed {
+			failed++
+		} else if !verbose {
+			continue
+		}
 
-	g, err := gounit.NewGenerator(opt, src, nil)
-	if err != nil {
-		return err
+		fmt.Fprintln(w, c)
 	}
 
-	testSrc := bytes.NewBuffer([]byte{})
-	if err := g.Write(testSrc); err != nil {
-		return fmt.Errorf("template produces invalid .go file\n%v\n%s", err, g.Source())
+	if failed > 0 {
+		return gounit.ErrTemplateCheckFailed.Format(failed, len(checks))
 	}
 
 	return nil
 }
 
-var testTemplate = `{{$func := .Func}}
+var testTemplate = `---
+name: default
+description: table-driven tests with reflect.DeepEqual assertions
+author: gounit
+version: 1.0.0
+---
+{{$func := .Func}}
 
 func {{ $func.TestName }}(t *testing.T) {
+	{{- if $func.IsGeneric }}
+		//TODO: replace placeholder type arguments
+		type (
+			{{- range $alias := typeAliases $func }}
+				{{ $alias }}
+			{{- end }}
+		)
+	{{ end -}}
 	{{- if (gt $func.NumParams 0) }}
 		type args struct {
 			{{ range $param := params $func }}
@@ -353,6 +445,30 @@ func {{ $func.TestName }}(t *testing.T) {
 		{{else}}
 			//{{ .Comment }}
 		{{end -}}
+		/*
+		{
+			name: "zero values",
+			{{- if $func.IsMethod }}
+			init: func(t *testing.T) {{ ast $func.ReceiverType }} {
+				{{ indent "\t\t\t\t" (construct $func) }}
+			},
+			{{- end }}
+			{{- if (gt $func.NumParams 0) }}
+			args: func(t *testing.T) args {
+				return args{
+					{{- range $param := params $func }}
+					{{ fieldName $param }}: {{ zero $func $param }},
+					{{- end }}
+				}
+			},
+			{{- end }}
+			{{- range $result := results $func }}
+			{{- with wantZero $func $result }}
+			{{ fieldName (want $result) }}: {{ . }},
+			{{- end }}
+			{{- end }}
+		},
+		*/
 	}
 
 	for _, tt := range tests {
@@ -370,7 +486,7 @@ func {{ $func.TestName }}(t *testing.T) {
 					tt.inspect(receiver, t)
 				}
 			{{ else }}
-				{{ if (gt $func.NumResults 0) }}{{ join $func.ResultsNames ", " }} := {{end}}{{$func.Name}}(
+				{{ if (gt $func.NumResults 0) }}{{ join $func.ResultsNames ", " }} := {{end}}{{$func.QualifiedName}}{{$func.TypeParamsList}}(
 					{{- range $i, $pn := $func.ParamsNames }}
 						{{- if not (eq $i 0)}},{{end}}tArgs.{{ $pn }}{{ end }})
 			{{end}}
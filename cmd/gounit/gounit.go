@@ -10,7 +10,10 @@ import (
 )
 
 func init() {
+	gounit.RegisterCommand("check", &CheckCommand{})
 	gounit.RegisterCommand("gen", &GenerateCommand{})
+	gounit.RegisterCommand("lsp", &LSPCommand{})
+	gounit.RegisterCommand("mock", &MockCommand{})
 	gounit.RegisterCommand("template", &TemplateCommand{})
 }
 
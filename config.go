@@ -0,0 +1,207 @@
+package gounit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrInvalidConfig = GenericError("invalid configuration file %s: %v")
+)
+
+//ConfigFileNames are names of the project configuration files in the order of precedence
+var ConfigFileNames = []string{".gounit.json", ".gounit.yaml", ".gounit.yml"}
+
+//Test package modes of the project configuration
+const (
+	PackageInternal = "internal"
+	PackageExternal = "external"
+)
+
+//ProjectConfig is a configuration of the repository or the directory, i.e.:
+//
+//	template: testify
+//	comment: add more test cases
+//	output: "{name}_gen_test.go"
+//	package: external
+//	naming: "Test{{.Type}}{{.Name}}"
+type ProjectConfig struct {
+	//Template is a name of the installed template or a path to the template file,
+	//relative paths are resolved against the directory of the configuration file
+	Template string `json:"template"`
+
+	//Comment is inserted into the generated tests
+	Comment string `json:"comment"`
+
+	//Output is a name of the test file, {name} is replaced with
+	//the name of the input file without .go extension
+	Output string `json:"output"`
+
+	//Package is a test package mode: "internal" or "external"
+	Package string `json:"package"`
+
+	//Naming is a naming scheme of the tests, see NamingScheme
+	Naming string `json:"naming"`
+
+	//Files are paths of the configuration files the config is loaded
+	//from starting with the nearest one
+	Files []string `json:"-"`
+}
+
+//LoadProjectConfig looks for the configuration files in the directory dir and all
+//its parents. Settings of the configuration files located closer to the dir override
+//the settings of the files located in the parent directories. If there are no
+//configuration files an empty config is returned
+func LoadProjectConfig(dir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []*ProjectConfig
+	for {
+		for _, name := range ConfigFileNames {
+			path := filepath.Join(dir, name)
+
+			data, err := ioutil.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			c, err := parseProjectConfig(path, data)
+			if err != nil {
+				return nil, ErrInvalidConfig.Format(path, err)
+			}
+
+			configs = append(configs, c)
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	config := &ProjectConfig{}
+	for i := len(configs) - 1; i >= 0; i-- {
+		config.merge(configs[i])
+	}
+
+	return config, nil
+}
+
+//parseProjectConfig parses the contents of the configuration file located at path
+func parseProjectConfig(path string, data []byte) (*ProjectConfig, error) {
+	c := &ProjectConfig{Files: []string{path}}
+
+	if filepath.Ext(path) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(c); err != nil {
+			return nil, err
+		}
+	} else {
+		fields, err := parseMetadata(string(data))
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range fields {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: string expected", key)
+			}
+
+			switch key {
+			case "template":
+				c.Template = s
+			case "comment":
+				c.Comment = s
+			case "output":
+				c.Output = s
+			case "package":
+				c.Package = s
+			case "naming":
+				c.Naming = s
+			default:
+				return nil, fmt.Errorf("unknown field %q", key)
+			}
+		}
+	}
+
+	switch c.Package {
+	case "", PackageInternal, PackageExternal:
+	default:
+		return nil, fmt.Errorf("package: %q or %q expected, got %q", PackageInternal, PackageExternal, c.Package)
+	}
+
+	if strings.ContainsAny(c.Output, `/\`) {
+		return nil, fmt.Errorf("output: file name expected, got %q", c.Output)
+	}
+
+	if c.Naming != "" {
+		if _, err := NewNamingScheme(c.Naming); err != nil {
+			return nil, err
+		}
+	}
+
+	//template file is looked up relative to the configuration file
+	if isPath(c.Template) && !filepath.IsAbs(c.Template) {
+		c.Template = filepath.Join(filepath.Dir(path), c.Template)
+	}
+
+	return c, nil
+}
+
+//merge overrides settings of the config with the settings of the c
+func (config *ProjectConfig) merge(c *ProjectConfig) {
+	if c.Template != "" {
+		config.Template = c.Template
+	}
+
+	if c.Comment != "" {
+		config.Comment = c.Comment
+	}
+
+	if c.Output != "" {
+		config.Output = c.Output
+	}
+
+	if c.Package != "" {
+		config.Package = c.Package
+	}
+
+	if c.Naming != "" {
+		config.Naming = c.Naming
+	}
+
+	config.Files = append(append([]string{}, c.Files...), config.Files...)
+}
+
+//OutputFile returns a name of the test file for the input file
+//or an empty string if the output file isn't configured
+func (config *ProjectConfig) OutputFile(inputFile string) string {
+	if config.Output == "" {
+		return ""
+	}
+
+	name := strings.TrimSuffix(filepath.Base(inputFile), ".go")
+	return filepath.Join(filepath.Dir(inputFile), strings.Replace(config.Output, "{name}", name, -1))
+}
+
+//isPath returns true if the template setting is a path to the template
+//file rather than a name of the installed template
+func isPath(template string) bool {
+	return strings.ContainsAny(template, `/\`) || strings.HasPrefix(template, ".")
+}
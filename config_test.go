@@ -0,0 +1,179 @@
+package gounit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadProjectConfig(t *testing.T) {
+	root, err := ioutil.TempDir("", "gounit_config")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	write := func(t *testing.T, path, contents string) {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	write(t, "repo/.gounit.yaml", "# repository defaults\ntemplate: ./templates/testify\ncomment: 'add more cases'\noutput: \"{name}_gen_test.go\"\nnaming: Test{{.Type}}{{.Name}}\n")
+	write(t, "repo/pkg/.gounit.json", `{"template": "minimock", "package": "external"}`)
+	write(t, "repo/pkg/sub/.gounit.yml", "")
+	write(t, "invalid/yaml/.gounit.yaml", "templates: testify\n")
+	write(t, "invalid/json/.gounit.json", `{"template": 1}`)
+	write(t, "invalid/package/.gounit.yaml", "package: black-box\n")
+	write(t, "invalid/output/.gounit.yaml", "output: ../{name}_test.go\n")
+	write(t, "invalid/naming/.gounit.json", `{"naming": "{{.Name}}"}`)
+
+	tests := []struct {
+		name string
+		dir  string
+
+		want1   *ProjectConfig
+		wantErr bool
+	}{
+		{
+			name:  "no config files",
+			dir:   root,
+			want1: &ProjectConfig{},
+		},
+		{
+			name: "repository config",
+			dir:  "repo",
+			want1: &ProjectConfig{
+				Template: filepath.Join(root, "repo", "templates", "testify"),
+				Comment:  "add more cases",
+				Output:   "{name}_gen_test.go",
+				Naming:   "Test{{.Type}}{{.Name}}",
+				Files:    []string{filepath.Join(root, "repo", ".gounit.yaml")},
+			},
+		},
+		{
+			name: "directory config overrides repository config",
+			dir:  "repo/pkg/sub",
+			want1: &ProjectConfig{
+				Template: "minimock",
+				Comment:  "add more cases",
+				Output:   "{name}_gen_test.go",
+				Package:  PackageExternal,
+				Naming:   "Test{{.Type}}{{.Name}}",
+				Files: []string{
+					filepath.Join(root, "repo", "pkg", "sub", ".gounit.yml"),
+					filepath.Join(root, "repo", "pkg", ".gounit.json"),
+					filepath.Join(root, "repo", ".gounit.yaml"),
+				},
+			},
+		},
+		{
+			name:    "unknown yaml field",
+			dir:     "invalid/yaml",
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			dir:     "invalid/json",
+			wantErr: true,
+		},
+		{
+			name:    "invalid package mode",
+			dir:     "invalid/package",
+			wantErr: true,
+		},
+		{
+			name:    "output outside of the package",
+			dir:     "invalid/output",
+			wantErr: true,
+		},
+		{
+			name:    "invalid naming scheme",
+			dir:     "invalid/naming",
+			wantErr: true,
+		},
+	}
+
+	outside := countConfigs(t, filepath.Dir(root))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tt.dir
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(root, dir)
+			}
+
+			got1, err := LoadProjectConfig(dir)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadProjectConfig error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			//config files located above the temp dir are ignored
+			got1.Files = got1.Files[:len(got1.Files)-outside]
+
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("LoadProjectConfig got1 = %#v, want1: %#v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+//countConfigs returns a number of the config files found in the dir and its parents
+func countConfigs(t *testing.T, dir string) int {
+	c, err := LoadProjectConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig: %v", err)
+	}
+
+	return len(c.Files)
+}
+
+func TestProjectConfig_OutputFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		inputFile string
+
+		want1 string
+	}{
+		{
+			name:      "not configured",
+			inputFile: "pkg/file.go",
+			want1:     "",
+		},
+		{
+			name:      "name pattern",
+			output:    "{name}_gen_test.go",
+			inputFile: "pkg/file.go",
+			want1:     filepath.Join("pkg", "file_gen_test.go"),
+		},
+		{
+			name:      "single file",
+			output:    "all_test.go",
+			inputFile: "pkg/file.go",
+			want1:     filepath.Join("pkg", "all_test.go"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ProjectConfig{Output: tt.output}
+
+			if got1 := config.OutputFile(tt.inputFile); got1 != tt.want1 {
+				t.Errorf("ProjectConfig.OutputFile got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package gounit
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+//Constructor returns the most suitable constructor of the method receiver
+//or nil if the function is not a method or no constructors were found
+func (f *Func) Constructor() *Func {
+	if len(f.Constructors) == 0 {
+		return nil
+	}
+
+	return f.Constructors[0]
+}
+
+//ConstructorCall returns an expression that calls the receiver's constructor with the
+//zero values of its params, i.e. "NewT(0, nil)". It returns an empty string if the
+//receiver doesn't have a constructor
+func (f *Func) ConstructorCall(fs *token.FileSet) string {
+	c := f.Constructor()
+	if c == nil {
+		return ""
+	}
+
+	var args []string
+	for i, param := range c.Params(fs) {
+		//variadic param is omitted
+		if i == c.NumParams()-1 && c.IsVariadic() {
+			break
+		}
+
+		args = append(args, c.ZeroValue(fs, param))
+	}
+
+	var typeArgs string
+	if c.IsGeneric() {
+		typeArgs = "[" + strings.Join(f.TypeParamsNames(), ", ") + "]"
+	}
+
+	return c.QualifiedName() + typeArgs + "(" + strings.Join(args, ", ") + ")"
+}
+
+//ConstructReceiver returns statements that create the method receiver using its
+//constructor and return it from the test's init function. Constructor errors are
+//reported with t.Fatalf
+func (f *Func) ConstructReceiver(fs *token.FileSet) string {
+	c := f.Constructor()
+	if c == nil {
+		return "return " + f.ZeroValue(fs, f.ReceiverType())
+	}
+
+	var (
+		call   = f.ConstructorCall(fs)
+		result = "r"
+	)
+
+	_, wantPointer := f.ReceiverType().(*ast.StarExpr)
+	_, gotPointer := c.Signature.Type.Results.List[0].Type.(*ast.StarExpr)
+
+	switch {
+	case wantPointer && !gotPointer:
+		result = "&r"
+	case !wantPointer && gotPointer:
+		result = "*r"
+	}
+
+	if !c.ReturnsError() {
+		if result == "r" {
+			return "return " + call
+		}
+
+		return fmt.Sprintf("r := %s\nreturn %s", call, result)
+	}
+
+	return fmt.Sprintf("r, err := %s\nif err != nil {\nt.Fatalf(\"%s: %%v\", err)\n}\n\nreturn %s", call, c.Name(), result)
+}
+
+//findConstructors returns a list of the functions that create a receiver of the method.
+//Constructor is a function that returns either T or *T and optionally an error.
+//Constructors named NewT and newT go first
+func findConstructors(method *Func, candidates []*Func) []*Func {
+	var (
+		typeName     = method.ReceiverTypeName()
+		numTypeArgs  = len(receiverTypeParams(method.ReceiverType()))
+		constructors []*Func
+	)
+
+	for _, c := range candidates {
+		numResults := c.NumResults()
+		if numResults == 0 || numResults > 2 || (numResults == 2 && !c.ReturnsError()) {
+			continue
+		}
+
+		if baseTypeName(c.firstResultType()) != typeName {
+			continue
+		}
+
+		//generic constructors are instantiated with the receiver's type params
+		if c.IsGeneric() && len(c.TypeParamsNames()) != numTypeArgs {
+			continue
+		}
+
+		constructors = append(constructors, c)
+	}
+
+	rank := func(c *Func) int {
+		switch c.Name() {
+		case "New" + typeName:
+			return 0
+		case "new" + typeName:
+			return 1
+		case "New":
+			return 2
+		}
+		return 3
+	}
+
+	sort.SliceStable(constructors, func(i, j int) bool {
+		return rank(constructors[i]) < rank(constructors[j])
+	})
+
+	return constructors
+}
+
+//firstResultType returns a type of the function's first result
+//if it's a possibly pointer named type or nil otherwise
+func (f *Func) firstResultType() ast.Expr {
+	if f.Signature.Type.Results == nil || len(f.Signature.Type.Results.List) == 0 {
+		return nil
+	}
+
+	t := f.Signature.Type.Results.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+
+	switch t.(type) {
+	case *ast.Ident, *ast.IndexExpr, *ast.IndexListExpr:
+		return t
+	}
+
+	return nil
+}
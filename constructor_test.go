@@ -0,0 +1,115 @@
+package gounit
+
+import (
+	"testing"
+)
+
+const constructorSource = `package constructors
+
+type T struct{}
+
+func (t *T) PointerMethod() {}
+
+func (t T) ValueMethod() {}
+
+func MakeT() T { return T{} }
+
+func NewT(a int, opts ...string) (*T, error) { return nil, nil }
+
+func newT() T { return T{} }
+
+type G[K comparable] struct{}
+
+func (g G[K]) Method() {}
+
+func NewG[K comparable]() G[K] { return G[K]{} }
+
+type Orphan struct{}
+
+func (o Orphan) Method() {}
+
+func notConstructor() (T, int) { return T{}, 0 }`
+
+func Test_findConstructors(t *testing.T) {
+	fs, funcs := parseFuncs(t, constructorSource)
+
+	var methods, candidates []*Func
+	for _, f := range funcs {
+		if f.IsMethod() {
+			methods = append(methods, f)
+		} else {
+			candidates = append(candidates, f)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		method *Func
+
+		wantConstructors []string
+		wantConstruct    string
+	}{
+		{
+			name:             "pointer receiver",
+			method:           methods[0],
+			wantConstructors: []string{"NewT", "newT", "MakeT"},
+			wantConstruct:    "r, err := NewT(0)\nif err != nil {\nt.Fatalf(\"NewT: %v\", err)\n}\n\nreturn r",
+		},
+		{
+			name:             "value receiver",
+			method:           methods[1],
+			wantConstructors: []string{"NewT", "newT", "MakeT"},
+			wantConstruct:    "r, err := NewT(0)\nif err != nil {\nt.Fatalf(\"NewT: %v\", err)\n}\n\nreturn *r",
+		},
+		{
+			name:             "generic receiver",
+			method:           methods[2],
+			wantConstructors: []string{"NewG"},
+			wantConstruct:    "return NewG[K]()",
+		},
+		{
+			name:          "no constructors",
+			method:        methods[3],
+			wantConstruct: "return Orphan{}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.method.Constructors = findConstructors(tt.method, candidates)
+
+			var got []string
+			for _, c := range tt.method.Constructors {
+				got = append(got, c.Name())
+			}
+
+			if len(got) != len(tt.wantConstructors) {
+				t.Fatalf("findConstructors got1 = %v, want1: %v", got, tt.wantConstructors)
+			}
+
+			for i := range got {
+				if got[i] != tt.wantConstructors[i] {
+					t.Errorf("findConstructors got1 = %v, want1: %v", got, tt.wantConstructors)
+				}
+			}
+
+			if got := tt.method.ConstructReceiver(fs); got != tt.wantConstruct {
+				t.Errorf("Func.ConstructReceiver got1 = %q, want1: %q", got, tt.wantConstruct)
+			}
+		})
+	}
+}
+
+func TestFunc_ConstructorCall(t *testing.T) {
+	fs, funcs := parseFuncs(t, constructorSource)
+
+	f := funcs[0]
+	if call := f.ConstructorCall(fs); call != "" {
+		t.Errorf("unexpected constructor call: %q", call)
+	}
+
+	f.Constructors = []*Func{funcs[3]}
+	if call := f.ConstructorCall(fs); call != "NewT(0)" {
+		t.Errorf("unexpected constructor call: %q", call)
+	}
+}
@@ -0,0 +1,200 @@
+package gounit
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrInvalidCoverProfile = GenericError("invalid coverage profile: %v")
+	ErrCoverProfileKind    = GenericError("coverage profile can't be used to generate %s functions")
+	ErrNotInCoverProfile   = GenericError("file not found in coverage profile")
+)
+
+//coverBlock is a block of statements of the coverage profile
+type coverBlock struct {
+	startLine, startCol int
+	endLine, endCol     int
+	numStmts            int
+	count               int
+}
+
+//CoverProfile is a coverage profile produced by go test -coverprofile
+type CoverProfile struct {
+	//blocks are coverage blocks by the file name, i.e. "example.com/pkg/file.go"
+	blocks map[string][]coverBlock
+}
+
+//ParseCoverProfile parses the coverage profile in the format produced by go test -coverprofile:
+//
+//	mode: set
+//	example.com/pkg/file.go:10.32,12.2 1 1
+func ParseCoverProfile(r io.Reader) (*CoverProfile, error) {
+	p := &CoverProfile{blocks: map[string][]coverBlock{}}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		filename, block, err := parseCoverBlock(line)
+		if err != nil {
+			return nil, ErrInvalidCoverProfile.Format(fmt.Sprintf("line %d: %v", n, err))
+		}
+
+		p.blocks[filename] = append(p.blocks[filename], block)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, ErrInvalidCoverProfile.Format(err)
+	}
+
+	return p, nil
+}
+
+//parseCoverBlock parses the line of the profile: "file.go:startLine.startCol,endLine.endCol numStmts count"
+func parseCoverBlock(line string) (string, coverBlock, error) {
+	var b coverBlock
+
+	i := strings.LastIndex(line, ":")
+	if i < 0 {
+		return "", b, fmt.Errorf("file name expected")
+	}
+
+	filename := line[:i]
+	if _, err := fmt.Sscanf(line[i+1:], "%d.%d,%d.%d %d %d", &b.startLine, &b.startCol, &b.endLine, &b.endCol, &b.numStmts, &b.count); err != nil {
+		return "", b, fmt.Errorf("%q: %v", line[i+1:], err)
+	}
+
+	return filename, b, nil
+}
+
+//fileBlocks returns the coverage blocks of the file. Files of the profile are named
+//by the import path of the package, so the blocks are looked up by the import path
+//of the file's directory. If the import path can't be resolved the file whose
+//path has the longest common suffix with the filename is picked, the suffix must
+//include the directory of the file and it must be unique. It returns false if
+//there is no such file
+func (p *CoverProfile) fileBlocks(filename string) ([]coverBlock, bool) {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+
+	if pkgPath, err := importPath(filepath.Dir(filename)); err == nil {
+		blocks, ok := p.blocks[pkgPath+"/"+filepath.Base(filename)]
+		return blocks, ok
+	}
+
+	path := strings.Split(filepath.ToSlash(filename), "/")
+
+	var (
+		best   []coverBlock
+		length int
+		unique bool
+	)
+
+	for name, blocks := range p.blocks {
+		profilePath := strings.Split(name, "/")
+
+		n := 0
+		for n < len(path) && n < len(profilePath) && path[len(path)-1-n] == profilePath[len(profilePath)-1-n] {
+			n++
+		}
+
+		switch {
+		case n > length:
+			best, length, unique = blocks, n, true
+		case n == length:
+			unique = false
+		}
+	}
+
+	//the base name of the file is not enough, i.e. a/util.go and b/util.go
+	return best, length > 1 && unique
+}
+
+//Coverage returns a percentage of the statements of the function covered by the tests
+//and false if the file the function is declared in isn't found in the profile
+func (p *CoverProfile) Coverage(fs *token.FileSet, filename string, f *Func) (float64, bool) {
+	blocks, ok := p.fileBlocks(filename)
+	if !ok {
+		return 0, false
+	}
+
+	start, end := fs.Position(f.Signature.Pos()), fs.Position(f.Signature.End())
+
+	var total, covered int
+	for _, b := range blocks {
+		if !before(start.Line, start.Column, b.startLine, b.startCol) || !before(b.endLine, b.endCol, end.Line, end.Column) {
+			continue
+		}
+
+		total += b.numStmts
+		if b.count > 0 {
+			covered += b.numStmts
+		}
+	}
+
+	//functions without statements have nothing to cover
+	if total == 0 {
+		return 100, true
+	}
+
+	return 100 * float64(covered) / float64(total), true
+}
+
+//before returns true if the position line1:col1 isn't after the position line2:col2
+func before(line1, col1, line2, col2 int) bool {
+	return line1 < line2 || line1 == line2 && col1 <= col2
+}
+
+//uncovered returns the functions that have no covered statements or whose
+//coverage is below the threshold. It returns ErrNotInCoverProfile if the
+//file isn't found in the profile, i.e. the profile is stale or it was
+//collected for another package
+func (p *CoverProfile) uncovered(fs *token.FileSet, filename string, funcs []*Func, threshold float64) ([]*Func, error) {
+	if _, ok := p.fileBlocks(filename); !ok {
+		return nil, ErrNotInCoverProfile
+	}
+
+	var result []*Func
+	for _, f := range funcs {
+		if pct, _ := p.Coverage(fs, filename, f); pct == 0 || pct < threshold {
+			result = append(result, f)
+		}
+	}
+
+	return result, nil
+}
+
+//coverageSuffix is added to the names of the tests generated in the coverage mode
+//when the tests with the same names already exist
+const coverageSuffix = "_coverage"
+
+//renameExisting changes names of the tests of the functions that clash with
+//the functions declared in the test files, i.e. "TestFoo_coverage" is used
+//if TestFoo already exists but doesn't cover Foo. Functions that already
+//have such tests are filtered out
+func renameExisting(funcs []*Func, declared map[string]bool) []*Func {
+	var result []*Func
+	for _, f := range funcs {
+		name := f.TestName()
+		if declared[name] {
+			if declared[name+coverageSuffix] {
+				continue
+			}
+
+			f.testName = name + coverageSuffix
+		}
+
+		result = append(result, f)
+	}
+
+	return result
+}
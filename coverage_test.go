@@ -0,0 +1,240 @@
+package gounit
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const coverageSource = `package cov
+
+func Abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func Sign(n int) int {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
+
+func Noop() {}
+`
+
+const coverageProfile = `mode: set
+example.com/cov/cov.go:3.21,4.11 1 1
+example.com/cov/cov.go:4.11,6.3 1 0
+example.com/cov/cov.go:7.2,7.10 1 1
+example.com/cov/cov.go:10.22,11.11 1 0
+example.com/cov/cov.go:11.11,13.3 1 0
+example.com/cov/cov.go:14.2,14.10 1 0
+example.com/cov/cov.go:17.13,17.14 0 0
+example.com/other/cov.go:3.21,4.11 1 1
+example.com/other/cov.go:4.11,6.3 1 1
+example.com/other/cov.go:7.2,7.10 1 1
+`
+
+//coverageModule returns a temporary directory of the example.com module
+//the files of the coverage profile belong to
+func coverageModule(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "gounit_coverage")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com\n"), 0600); err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	return dir
+}
+
+func TestParseCoverProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+
+		want1   int
+		wantErr bool
+	}{
+		{name: "valid profile", profile: coverageProfile, want1: 2},
+		{name: "empty profile", profile: "mode: count\n", want1: 0},
+		{name: "missing file name", profile: "mode: set\n3.21,4.11 1 1\n", wantErr: true},
+		{name: "invalid block", profile: "mode: set\ncov.go:3.21,4.11 one 1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCoverProfile(strings.NewReader(tt.profile))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCoverProfile error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if err == nil && len(got.blocks) != tt.want1 {
+				t.Errorf("ParseCoverProfile got %d files, want1: %d", len(got.blocks), tt.want1)
+			}
+		})
+	}
+}
+
+func TestCoverProfile_Coverage(t *testing.T) {
+	profile, err := ParseCoverProfile(strings.NewReader(coverageProfile))
+	if err != nil {
+		t.Fatalf("ParseCoverProfile: %v", err)
+	}
+
+	dir := coverageModule(t)
+	defer os.RemoveAll(dir)
+
+	//the directory outside of the module and GOPATH
+	outside, err := ioutil.TempDir("", "gounit_coverage")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(outside)
+
+	g, err := NewGenerator(Options{All: true, Template: "{{ $func := .Func }}"}, strings.NewReader(coverageSource), nil)
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		fn       int
+
+		want1 float64
+		want2 bool
+	}{
+		{name: "partially covered", filename: filepath.Join(dir, "cov", "cov.go"), fn: 0, want1: 200.0 / 3, want2: true},
+		{name: "not covered", filename: filepath.Join(dir, "cov", "cov.go"), fn: 1, want1: 0, want2: true},
+		{name: "no statements", filename: filepath.Join(dir, "cov", "cov.go"), fn: 2, want1: 100, want2: true},
+		{name: "file of another package", filename: filepath.Join(dir, "other", "cov.go"), fn: 0, want1: 100, want2: true},
+		{name: "file not found", filename: filepath.Join(dir, "cov", "other_file.go"), fn: 0, want1: 0, want2: false},
+		{name: "package not found", filename: filepath.Join(dir, "util", "cov.go"), fn: 0, want1: 0, want2: false},
+		{name: "nested package", filename: filepath.Join(dir, "sub", "cov", "cov.go"), fn: 0, want1: 0, want2: false},
+		{name: "package outside of the module", filename: filepath.Join(outside, "other", "cov.go"), fn: 0, want1: 100, want2: true},
+		{name: "base name outside of the module", filename: filepath.Join(outside, "cov.go"), fn: 0, want1: 0, want2: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1, got2 := profile.Coverage(g.fs, tt.filename, g.Funcs()[tt.fn])
+
+			if got1 != tt.want1 {
+				t.Errorf("CoverProfile.Coverage got1 = %v, want1: %v", got1, tt.want1)
+			}
+
+			if got2 != tt.want2 {
+				t.Errorf("CoverProfile.Coverage got2 = %v, want2: %v", got2, tt.want2)
+			}
+		})
+	}
+}
+
+func TestNewGenerator_coverage(t *testing.T) {
+	profile, err := ParseCoverProfile(strings.NewReader(coverageProfile))
+	if err != nil {
+		t.Fatalf("ParseCoverProfile: %v", err)
+	}
+
+	dir := coverageModule(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name      string
+		threshold float64
+		kind      Kind
+		testSrc   string
+		inputFile string
+
+		want       string
+		wantErr    bool
+		inspectErr func(err error, t *testing.T)
+	}{
+		{
+			name: "uncovered functions",
+			want: "TestSign",
+		},
+		{
+			name:      "functions below the threshold",
+			threshold: 90,
+			want:      "TestAbs,TestSign",
+		},
+		{
+			name:      "names clash with the existing tests",
+			threshold: 90,
+			testSrc:   "package cov\n\nfunc TestAbs(t *testing.T) {}\n\nfunc TestSign(t *testing.T) {}\n\nfunc TestSign_coverage(t *testing.T) {}\n",
+			want:      "TestAbs_coverage",
+		},
+		{
+			name:    "benchmarks",
+			kind:    KindBenchmark,
+			wantErr: true,
+		},
+		{
+			name:      "file not found in the profile",
+			inputFile: filepath.Join(dir, "untested", "cov.go"),
+			wantErr:   true,
+			inspectErr: func(err error, t *testing.T) {
+				if err != ErrNotInCoverProfile {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputFile := tt.inputFile
+			if inputFile == "" {
+				inputFile = filepath.Join(dir, "cov", "cov.go")
+			}
+
+			opt := Options{
+				All:            true,
+				InputFile:      inputFile,
+				Template:       "{{ $func := .Func }}",
+				Kind:           tt.kind,
+				CoverProfile:   profile,
+				CoverThreshold: tt.threshold,
+			}
+
+			var testSrc io.Reader
+			if tt.testSrc != "" {
+				testSrc = strings.NewReader(tt.testSrc)
+			}
+
+			g, err := NewGenerator(opt, strings.NewReader(coverageSource), testSrc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGenerator error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if tt.inspectErr != nil {
+				tt.inspectErr(err, t)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := testNames(g.Funcs()); got != tt.want {
+				t.Errorf("Generator.Funcs = %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_before(t *testing.T) {
+	if !before(1, 1, 1, 1) || !before(1, 5, 2, 1) || before(2, 1, 1, 5) || before(1, 5, 1, 4) {
+		t.Errorf("before: unexpected order of the positions")
+	}
+}
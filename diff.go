@@ -0,0 +1,204 @@
+package gounit
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+//number of unchanged lines shown around the changes
+const diffContext = 3
+
+//diffOp is an operation of the edit script: ' ' keeps the line, '-' deletes
+//the line of the old text and '+' inserts the line of the new text
+type diffOp struct {
+	kind byte
+	line string
+}
+
+//UnifiedDiff returns a unified diff between the old and the new texts or an
+//empty string if the texts are equal. Pass "/dev/null" as the oldName when
+//the old file doesn't exist
+func UnifiedDiff(oldName, newName string, oldText, newText []byte) string {
+	if bytes.Equal(oldText, newText) {
+		return ""
+	}
+
+	ops := diffLines(splitLines(string(oldText)), splitLines(string(newText)))
+
+	buf := bytes.NewBuffer([]byte{})
+	fmt.Fprintf(buf, "--- %s\n+++ %s\n", oldName, newName)
+
+	//line numbers of the old and the new text before the ops[i]
+	oldLines := make([]int, len(ops)+1)
+	newLines := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldLines[i+1], newLines[i+1] = oldLines[i], newLines[i]
+		if op.kind != '+' {
+			oldLines[i+1]++
+		}
+		if op.kind != '-' {
+			newLines[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+
+		//hunk ends when there are more than 2*diffContext unchanged lines in a row
+		end, unchanged := i, 0
+		for ; end < len(ops) && unchanged <= 2*diffContext; end++ {
+			if ops[end].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+
+		if trim := unchanged - diffContext; trim > 0 {
+			end -= trim
+		}
+
+		fmt.Fprintf(buf, "@@ -%s +%s @@\n",
+			hunkRange(oldLines[start], oldLines[end]-oldLines[start]),
+			hunkRange(newLines[start], newLines[end]-newLines[start]),
+		)
+
+		for _, op := range ops[start:end] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	return buf.String()
+}
+
+//hunkRange returns the range of the hunk in the "start,length" format
+//where start is a 1-based number of the first line of the hunk
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+//splitLines splits the text into lines keeping the line endings
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+//diffLines returns the shortest edit script that turns a into b
+//using the Myers' diff algorithm
+func diffLines(a, b []string) []diffOp {
+	var (
+		n, m   = len(a), len(b)
+		offset = n + m + 1
+		v      = make([]int, 2*offset+1)
+
+		//trace[d] is a snapshot of v[offset-d-1:offset+d+2] before the step d,
+		//these are the only diagonals the step d reads
+		trace [][]int
+	)
+
+	//the whole text is either inserted or deleted
+	if n == 0 || m == 0 {
+		ops := make([]diffOp, 0, n+m)
+		for _, line := range a {
+			ops = append(ops, diffOp{kind: '-', line: line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{kind: '+', line: line})
+		}
+
+		return ops
+	}
+
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	var (
+		ops  []diffOp
+		x, y = n, m
+	)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		//index of the diagonal k in the snapshot
+		i := k + d + 1
+
+		prevK := k - 1
+		if k == -d || (k != d && v[i-1] < v[i+1]) {
+			prevK = k + 1
+		}
+
+		prevX := v[prevK+d+1]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{kind: ' ', line: a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: '+', line: b[y-1]})
+			} else {
+				ops = append(ops, diffOp{kind: '-', line: a[x-1]})
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	//ops were collected from the end
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
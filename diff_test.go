@@ -0,0 +1,144 @@
+package gounit
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := func(from, to int) string {
+		var s string
+		for i := from; i <= to; i++ {
+			s += string(rune('a'+i-1)) + "\n"
+		}
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		oldName string
+		oldText string
+		newText string
+
+		want1 string
+	}{
+		{
+			name:    "equal texts",
+			oldText: "a\n",
+			newText: "a\n",
+			want1:   "",
+		},
+		{
+			name:    "new file",
+			oldName: "/dev/null",
+			newText: "a\nb\n",
+			want1:   "--- /dev/null\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "appended lines",
+			oldName: "a",
+			oldText: lines(1, 5),
+			newText: lines(1, 6),
+			want1:   "--- a\n+++ b\n@@ -3,3 +3,4 @@\n c\n d\n e\n+f\n",
+		},
+		{
+			name:    "separate hunks",
+			oldName: "a",
+			oldText: lines(1, 12),
+			newText: strings.Replace(strings.Replace(lines(1, 12), "b\n", "B\n", 1), "k\n", "K\n", 1),
+			want1: "--- a\n+++ b\n@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+				"@@ -8,5 +8,5 @@\n h\n i\n j\n-k\n+K\n l\n",
+		},
+		{
+			name:    "merged hunks",
+			oldName: "a",
+			oldText: lines(1, 9),
+			newText: strings.Replace(strings.Replace(lines(1, 9), "b\n", "B\n", 1), "i\n", "I\n", 1),
+			want1:   "--- a\n+++ b\n@@ -1,9 +1,9 @@\n a\n-b\n+B\n c\n d\n e\n f\n g\n h\n-i\n+I\n",
+		},
+		{
+			name:    "no newline at end of file",
+			oldName: "a",
+			oldText: "a",
+			newText: "a\nb\n",
+			want1:   "--- a\n+++ b\n@@ -1,1 +1,2 @@\n-a\n\\ No newline at end of file\n+a\n+b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := UnifiedDiff(tt.oldName, "b", []byte(tt.oldText), []byte(tt.newText))
+
+			if got1 != tt.want1 {
+				t.Errorf("UnifiedDiff got1 = %q, want1: %q", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff_large(t *testing.T) {
+	var oldText, newText strings.Builder
+	for i := 0; i < 15000; i++ {
+		fmt.Fprintf(&oldText, "line %d\n", i)
+		if i%1000 == 0 {
+			fmt.Fprintf(&newText, "changed line %d\n", i)
+		} else {
+			fmt.Fprintf(&newText, "line %d\n", i)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+
+		wantPrefix string
+		wantLines  int
+	}{
+		{
+			name:       "new file",
+			newText:    newText.String(),
+			wantPrefix: "--- old\n+++ new\n@@ -0,0 +1,15000 @@\n+changed line 0\n",
+			wantLines:  15003,
+		},
+		{
+			name:       "removed file",
+			oldText:    oldText.String(),
+			wantPrefix: "--- old\n+++ new\n@@ -1,15000 +0,0 @@\n-line 0\n",
+			wantLines:  15003,
+		},
+		{
+			name:       "changed lines",
+			oldText:    oldText.String(),
+			newText:    newText.String(),
+			wantPrefix: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-line 0\n+changed line 0\n",
+			wantLines:  2 + 15*9 - 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+
+			got1 := UnifiedDiff("old", "new", []byte(tt.oldText), []byte(tt.newText))
+
+			runtime.ReadMemStats(&after)
+
+			if !strings.HasPrefix(got1, tt.wantPrefix) {
+				t.Errorf("UnifiedDiff got1 = %.200q..., want prefix: %q", got1, tt.wantPrefix)
+			}
+
+			if got := strings.Count(got1, "\n"); got != tt.wantLines {
+				t.Errorf("UnifiedDiff got %d lines, want: %d", got, tt.wantLines)
+			}
+
+			//the trace of the whole v slice per step takes hundreds of megabytes here
+			if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 32<<20 {
+				t.Errorf("UnifiedDiff allocated %d bytes", allocated)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package gounit
+
+import (
+	"strings"
+)
+
+//directivePrefix starts the directives controlling generation of the tests, i.e.:
+//
+//	//gounit:skip
+//	//gounit:template testify
+//	//gounit:name TestCustomName
+const directivePrefix = "//gounit:"
+
+//Doc returns a text of the function doc comment without directives
+func (f *Func) Doc() string {
+	if f.Signature == nil {
+		return ""
+	}
+
+	return f.Signature.Doc.Text()
+}
+
+//Directive returns an argument of the //gounit:<name> directive declared in the
+//doc comment of the function and true if the directive is declared
+func (f *Func) Directive(name string) (string, bool) {
+	if f.Signature == nil || f.Signature.Doc == nil {
+		return "", false
+	}
+
+	for _, c := range f.Signature.Doc.List {
+		if !strings.HasPrefix(c.Text, directivePrefix) {
+			continue
+		}
+
+		//like //go: directives the name must follow the prefix without spaces
+		fields := strings.Fields(strings.TrimPrefix(c.Text, directivePrefix))
+		if len(fields) > 0 && fields[0] == name && strings.HasPrefix(c.Text, directivePrefix+name) {
+			return strings.Join(fields[1:], " "), true
+		}
+	}
+
+	return "", false
+}
+
+//IsSkipped returns true if the function is marked with //gounit:skip directive
+func (f *Func) IsSkipped() bool {
+	_, skip := f.Directive("skip")
+	return skip
+}
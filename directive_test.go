@@ -0,0 +1,162 @@
+package gounit
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestFunc_Directive(t *testing.T) {
+	tests := []struct {
+		name      string
+		decl      string
+		directive string
+
+		want1 string
+		want2 bool
+	}{
+		{
+			name:      "no doc comment",
+			decl:      "func f() {}",
+			directive: "skip",
+		},
+		{
+			name:      "directive without argument",
+			decl:      "//f does nothing\n//gounit:skip\nfunc f() {}",
+			directive: "skip",
+			want2:     true,
+		},
+		{
+			name:      "directive with argument",
+			decl:      "//gounit:skip\n//gounit:template  testify\nfunc f() {}",
+			directive: "template",
+			want1:     "testify",
+			want2:     true,
+		},
+		{
+			name:      "directive is not declared",
+			decl:      "//gounit:skipped\n//gounit: skip\nfunc f() {}",
+			directive: "skip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1, got2 := parseFunc(t, token.NewFileSet(), tt.decl).Directive(tt.directive)
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.Directive got1 = %q, want1: %q", got1, tt.want1)
+			}
+
+			if got2 != tt.want2 {
+				t.Errorf("Func.Directive got2 = %v, want2: %v", got2, tt.want2)
+			}
+		})
+	}
+}
+
+func TestFunc_Doc(t *testing.T) {
+	f := parseFunc(t, token.NewFileSet(), "//f does nothing\n//\n//gounit:name TestNothing\nfunc f() {}")
+
+	if got, want := f.Doc(), "f does nothing\n"; got != want {
+		t.Errorf("Func.Doc got = %q, want: %q", got, want)
+	}
+
+	if got, want := f.TestName(), "TestNothing"; got != want {
+		t.Errorf("Func.TestName got = %q, want: %q", got, want)
+	}
+}
+
+func TestNewGenerator_directives(t *testing.T) {
+	const src = `package directives
+
+//Get is a generated accessor
+//gounit:skip
+func Get() int { return 0 }
+
+//Sum returns a sum of a and b
+//gounit:name TestSum_custom
+func Sum(a, b int) int { return a + b }
+
+//Custom is tested with the custom template
+//gounit:template custom
+func Custom() {}
+`
+
+	tests := []struct {
+		name         string
+		loadTemplate func(string) (string, error)
+
+		wantTests   string
+		wantSkipped string
+		wantCode    []string
+		wantErr     bool
+	}{
+		{
+			name: "directives",
+			loadTemplate: func(name string) (string, error) {
+				return "---\nimports: [os]\n---\n{{ $func := .Func }}\nfunc {{ $func.TestName }}(t *testing.T) { //" + name + "\n_ = os.Args }\n", nil
+			},
+			wantTests:   "TestSum_custom,TestCustom",
+			wantSkipped: "TestGet",
+			wantCode: []string{
+				"func TestSum_custom(t *testing.T) { //Sum returns a sum of a and b\n}",
+				"func TestCustom(t *testing.T) { //custom",
+				`"os"`,
+			},
+		},
+		{
+			name:    "templates can't be loaded",
+			wantErr: true,
+		},
+		{
+			name: "template is not found",
+			loadTemplate: func(name string) (string, error) {
+				return "", fmt.Errorf("template %s is not found", name)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := Options{
+				All:          true,
+				InputFile:    "directives.go",
+				OutputFile:   "directives_test.go",
+				Template:     "{{ $func := .Func }}\nfunc {{ $func.TestName }}(t *testing.T) { //{{ $func.Doc }}}\n",
+				LoadTemplate: tt.loadTemplate,
+			}
+
+			g, err := NewGenerator(opt, strings.NewReader(src), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGenerator error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := testNames(g.Funcs()); got != tt.wantTests {
+				t.Errorf("Generator.Funcs = %v, want: %v", got, tt.wantTests)
+			}
+
+			if got := testNames(g.Skipped()); got != tt.wantSkipped {
+				t.Errorf("Generator.Skipped = %v, want: %v", got, tt.wantSkipped)
+			}
+
+			buf := bytes.NewBuffer([]byte{})
+			if err := g.Write(buf); err != nil {
+				t.Fatalf("Generator.Write: %v", err)
+			}
+
+			for _, want := range tt.wantCode {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("generated code doesn't contain %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
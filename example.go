@@ -0,0 +1,27 @@
+package gounit
+
+import (
+	"go/token"
+)
+
+//ZeroArgs returns zero values of the params to call the function with,
+//one per param even if the params are unnamed, the variadic param is omitted
+func (f *Func) ZeroArgs(fs *token.FileSet) []string {
+	if f.Signature.Type.Params == nil {
+		return nil
+	}
+
+	args := []string{}
+	for i, p := range f.Signature.Type.Params.List {
+		if i == len(f.Signature.Type.Params.List)-1 && f.IsVariadic() {
+			break
+		}
+
+		zero := f.ZeroValue(fs, p.Type)
+		for n := numNames(p); n > 0; n-- {
+			args = append(args, zero)
+		}
+	}
+
+	return args
+}
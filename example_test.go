@@ -0,0 +1,53 @@
+package gounit
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestFunc_ZeroArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+
+		want1 string
+	}{
+		{
+			name:  "no params",
+			decl:  "func f() {}",
+			want1: "",
+		},
+		{
+			name:  "params",
+			decl:  "func f(a, b int, s string, m map[string]int, p *T) {}",
+			want1: `0, 0, "", map[string]int{}, nil`,
+		},
+		{
+			name:  "unnamed params",
+			decl:  "func f(int, int, string) {}",
+			want1: `0, 0, ""`,
+		},
+		{
+			name:  "variadic unnamed param is omitted",
+			decl:  "func f(string, ...string) {}",
+			want1: `""`,
+		},
+		{
+			name:  "variadic param is omitted",
+			decl:  "func f(sep string, parts ...string) {}",
+			want1: `""`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := token.NewFileSet()
+			got1 := strings.Join(parseFunc(t, fs, tt.decl).ZeroArgs(fs), ", ")
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.ZeroArgs got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
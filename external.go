@@ -0,0 +1,208 @@
+package gounit
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+//QualifiedName returns a name of the function to call it from the test. Functions
+//tested from the external test package are qualified with the source package name, i.e. "foo.Bar".
+//Names of the methods are never qualified
+func (f *Func) QualifiedName() string {
+	if f.pkg == "" || f.IsMethod() {
+		return f.Name()
+	}
+
+	return f.pkg + "." + f.Name()
+}
+
+//IsExported returns true if the function can be called from the external test
+//package, i.e. it's exported and it's not a method of an unexported type
+func (f *Func) IsExported() bool {
+	if !ast.IsExported(f.Name()) {
+		return false
+	}
+
+	return !f.IsMethod() || ast.IsExported(f.ReceiverTypeName())
+}
+
+//UnexportedTypes returns names of the unexported types of the source package
+//referenced by the signature of the function. Such functions can't be tested
+//from the external test package since the test can't declare their args and results
+func (f *Func) UnexportedTypes() []string {
+	typeParams := map[string]bool{}
+	for _, name := range f.TypeParamsNames() {
+		typeParams[name] = true
+	}
+
+	var (
+		names []string
+		seen  = map[string]bool{}
+		visit func(n ast.Node) bool
+	)
+
+	visit = func(n ast.Node) bool {
+		switch t := n.(type) {
+		case *ast.SelectorExpr:
+			//types of the imported packages
+			return false
+		case *ast.Field:
+			//names of the params of the func types and the fields of the struct types aren't types
+			ast.Inspect(t.Type, visit)
+			return false
+		case *ast.Ident:
+			if f.typeSpecs[t.Name] != nil && !typeParams[t.Name] && !ast.IsExported(t.Name) && !seen[t.Name] {
+				seen[t.Name] = true
+				names = append(names, t.Name)
+			}
+		}
+
+		return true
+	}
+
+	for _, list := range []*ast.FieldList{f.Signature.Recv, f.Signature.Type.Params, f.Signature.Type.Results} {
+		if list != nil {
+			ast.Inspect(list, visit)
+		}
+	}
+
+	return names
+}
+
+//externalize splits funcs into the functions that can be tested from the
+//external test package and the skipped unexported functions and the functions
+//whose signatures reference unexported types
+func externalize(funcs []*Func, pkg string) (exported, skipped []*Func) {
+	for _, f := range funcs {
+		if !f.IsExported() || len(f.UnexportedTypes()) > 0 {
+			skipped = append(skipped, f)
+			continue
+		}
+
+		f.externalize(pkg)
+
+		var constructors []*Func
+		for _, c := range f.Constructors {
+			if c.IsExported() && len(c.UnexportedTypes()) == 0 {
+				c.externalize(pkg)
+				constructors = append(constructors, c)
+			}
+		}
+		f.Constructors = constructors
+
+		exported = append(exported, f)
+	}
+
+	return exported, skipped
+}
+
+//externalize prepares the function to be tested from the external test package:
+//the package level types of the source package referenced by the receiver, params
+//and results are qualified with the package name
+func (f *Func) externalize(pkg string) {
+	if f.pkg == pkg {
+		return
+	}
+
+	f.pkg = pkg
+
+	q := typeQualifier{pkg: pkg, typeSpecs: f.typeSpecs, typeParams: map[string]bool{}}
+	for _, name := range f.TypeParamsNames() {
+		q.typeParams[name] = true
+	}
+
+	q.fields(f.Signature.Recv)
+	q.fields(f.Signature.Type.Params)
+	q.fields(f.Signature.Type.Results)
+}
+
+//typeQualifier qualifies the types declared in the source package with the package name
+type typeQualifier struct {
+	pkg        string
+	typeSpecs  map[string]*ast.TypeSpec
+	typeParams map[string]bool
+}
+
+//expr qualifies the type expression in place and returns
+//the qualified expression
+func (q typeQualifier) expr(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if q.typeSpecs[t.Name] != nil && !q.typeParams[t.Name] {
+			//position of the original identifier keeps the printer from breaking the lines
+			return &ast.SelectorExpr{X: &ast.Ident{NamePos: t.NamePos, Name: q.pkg}, Sel: &ast.Ident{NamePos: t.NamePos, Name: t.Name}}
+		}
+	case *ast.StarExpr:
+		t.X = q.expr(t.X)
+	case *ast.ParenExpr:
+		t.X = q.expr(t.X)
+	case *ast.Ellipsis:
+		t.Elt = q.expr(t.Elt)
+	case *ast.ArrayType:
+		t.Elt = q.expr(t.Elt)
+	case *ast.MapType:
+		t.Key = q.expr(t.Key)
+		t.Value = q.expr(t.Value)
+	case *ast.ChanType:
+		t.Value = q.expr(t.Value)
+	case *ast.IndexExpr:
+		t.X = q.expr(t.X)
+		t.Index = q.expr(t.Index)
+	case *ast.IndexListExpr:
+		t.X = q.expr(t.X)
+		for i := range t.Indices {
+			t.Indices[i] = q.expr(t.Indices[i])
+		}
+	case *ast.FuncType:
+		q.fields(t.Params)
+		q.fields(t.Results)
+	case *ast.StructType:
+		q.fields(t.Fields)
+	case *ast.InterfaceType:
+		q.fields(t.Methods)
+	}
+
+	return expr
+}
+
+//fields qualifies types of the fields in place
+func (q typeQualifier) fields(list *ast.FieldList) {
+	if list == nil {
+		return
+	}
+
+	for _, field := range list.List {
+		field.Type = q.expr(field.Type)
+	}
+}
+
+//isSourcePackage returns true if the expression is the qualifier of
+//the source package in the external test package
+func (f *Func) isSourcePackage(x ast.Expr) bool {
+	ident, ok := x.(*ast.Ident)
+	return ok && f.pkg != "" && ident.Name == f.pkg
+}
+
+//externalQualifier returns a types.Qualifier that qualifies all types with the
+//package name including the types declared in the source package
+func externalQualifier(p *types.Package) string {
+	return p.Name()
+}
+
+//hasImport returns true if the package with the given import path is imported by one of the specs
+func hasImport(specs []*ast.ImportSpec, path string) bool {
+	for _, spec := range specs {
+		if p, err := strconv.Unquote(spec.Path.Value); err == nil && p == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+//importSpec returns an import spec of the package with the given import path
+func importSpec(path string) *ast.ImportSpec {
+	return &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)}}
+}
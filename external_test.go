@@ -0,0 +1,194 @@
+package gounit
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const externalSource = `package ext
+
+type Config struct{ N int }
+
+type T struct{}
+
+type G[K comparable] struct{}
+
+func NewT(c Config) *T { return &T{} }
+
+func (t *T) Do(c *Config, m map[string][]Config, f func(Config) error) (Config, error) { return *c, nil }
+
+func (g G[K]) Get(k K) K { return k }
+
+func helper() {}
+
+func (t *T) private() {}
+
+type hidden struct{ N int }
+
+func Hide(h hidden) {}
+
+func Reveal(f func(hidden int) string) map[string][]*hidden { return nil }
+
+type U struct{}
+
+func NewU(h *hidden) *U { return &U{} }
+
+func (u *U) Run() {}
+`
+
+const externalTemplate = `{{$func := .Func}}
+func {{ $func.TestName }}(t *testing.T) {
+	{{ if $func.IsMethod }}{{ construct $func }}{{ end }}
+	//{{ $func.QualifiedName }}({{ join (params $func) ", " }}) {{ join (results $func) ", " }}
+}
+`
+
+func TestNewGenerator_external(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_external")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/ext\n"), 0600); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	type args struct {
+		opt     Options
+		testSrc string
+	}
+
+	tests := []struct {
+		name string
+		args args
+
+		wantTests   []string
+		wantSkipped []string
+		wantCode    []string
+		wantErr     bool
+	}{
+		{
+			name: "external package",
+			args: args{opt: Options{ExternalPackage: true}},
+
+			wantTests:   []string{"TestNewT", "TestT_Do", "TestG_Get", "TestU_Run"},
+			wantSkipped: []string{"Test_helper", "TestT_private", "TestHide", "TestReveal", "TestNewU"},
+			wantCode: []string{
+				"package ext_test",
+				`"example.com/ext"`,
+				"//ext.NewT(c ext.Config) got1 *ext.T",
+				"return ext.NewT(ext.Config{})",
+				"//Do(c *ext.Config, m map[string][]ext.Config, f func(ext.Config) error) got1 ext.Config",
+				"//Get(k K) got1 K",
+				"return &ext.U{}",
+			},
+		},
+		{
+			name: "existing external test file",
+			args: args{testSrc: "package ext_test\n"},
+
+			wantTests:   []string{"TestNewT", "TestT_Do", "TestG_Get", "TestU_Run"},
+			wantSkipped: []string{"Test_helper", "TestT_private", "TestHide", "TestReveal", "TestNewU"},
+			wantCode:    []string{"package ext_test", "//ext.NewT(c ext.Config) got1 *ext.T"},
+		},
+		{
+			name:    "existing internal test file",
+			args:    args{opt: Options{ExternalPackage: true}, testSrc: "package ext\n"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := tt.args.opt
+			opt.All = true
+			opt.InputFile = filepath.Join(dir, "ext.go")
+			opt.OutputFile = filepath.Join(dir, "ext_test.go")
+			opt.Template = externalTemplate
+
+			var testSrc io.Reader
+			if tt.args.testSrc != "" {
+				testSrc = strings.NewReader(tt.args.testSrc)
+			}
+
+			g, err := NewGenerator(opt, strings.NewReader(externalSource), testSrc)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGenerator error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := testNames(g.Funcs()); got != strings.Join(tt.wantTests, ",") {
+				t.Errorf("Generator.Funcs = %v, want: %v", got, tt.wantTests)
+			}
+
+			if got := testNames(g.Skipped()); got != strings.Join(tt.wantSkipped, ",") {
+				t.Errorf("Generator.Skipped = %v, want: %v", got, tt.wantSkipped)
+			}
+
+			buf := bytes.NewBuffer([]byte{})
+			if err := g.Write(buf); err != nil {
+				t.Fatalf("Generator.Write: %v", err)
+			}
+
+			for _, want := range tt.wantCode {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("generated code doesn't contain %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestFunc_UnexportedTypes(t *testing.T) {
+	g, err := NewGenerator(Options{All: true, Template: externalTemplate}, strings.NewReader(externalSource), nil)
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	want1 := map[string]string{
+		"TestT_Do":   "",
+		"TestG_Get":  "",
+		"TestHide":   "hidden",
+		"TestReveal": "hidden",
+		"TestNewU":   "hidden",
+	}
+
+	for _, f := range g.Funcs() {
+		want, ok := want1[f.TestName()]
+		if !ok {
+			continue
+		}
+
+		if got1 := strings.Join(f.UnexportedTypes(), ","); got1 != want {
+			t.Errorf("%s: Func.UnexportedTypes got1 = %v, want1: %v", f.Name(), got1, want)
+		}
+	}
+	//names of the params of the func types aren't types
+	f := parseFunc(t, token.NewFileSet(), "func Named(f func(hidden int), s struct{ hidden string }) {}")
+	f.typeSpecs = map[string]*ast.TypeSpec{"hidden": {}}
+
+	if got1 := f.UnexportedTypes(); len(got1) != 0 {
+		t.Errorf("Func.UnexportedTypes got1 = %v, want1: []", got1)
+	}
+}
+
+func testNames(funcs []*Func) string {
+	var names []string
+	for _, f := range funcs {
+		names = append(names, f.TestName())
+	}
+
+	return strings.Join(names, ",")
+}
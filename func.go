@@ -4,12 +4,42 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
 )
 
 //Func is a wrapper around ast.FuncDecl containing few methods
 //to use within a test template
 type Func struct {
 	Signature *ast.FuncDecl
+
+	//Object is a type-checked function, it's nil unless the source package
+	//is loaded with the type information (see Options.TypeCheck)
+	Object *types.Func
+
+	//Constructors is a list of functions that create the method receiver,
+	//see Constructor method
+	Constructors []*Func
+
+	//typeSpecs are type declarations of the source package
+	typeSpecs map[string]*ast.TypeSpec
+
+	//testTypes are names of the types declared in the test files of the package
+	testTypes map[string]bool
+
+	//pkg is a name of the source package, it's set when the function
+	//is tested from the external test package
+	pkg string
+
+	//naming is a naming scheme of the tests, the default scheme is used if it's nil
+	naming *NamingScheme
+
+	//declared are names of the functions and the methods (as "T.Method")
+	//declared in the source package
+	declared map[string]bool
+
+	//testName overrides the name of the test, it's set when the name
+	//clashes with the existing test in the coverage mode
+	testName string
 }
 
 //NewFunc returns pointer to the Func struct
@@ -138,17 +168,46 @@ func (f *Func) Name() string {
 	return f.Signature.Name.String()
 }
 
-//TestName returns a name of the test
+//TestName returns a name of the test according to the naming scheme,
+//it can be changed with the //gounit:name directive
 func (f *Func) TestName() string {
-	name := "Test"
+	if f.testName != "" {
+		return f.testName
+	}
+
+	if name, ok := f.Directive("name"); ok && name != "" {
+		return name
+	}
+
+	if f.naming != nil {
+		return f.naming.TestName(f)
+	}
+
+	return f.prefixedName("Test")
+}
+
+//BenchmarkName returns a name of the benchmark
+func (f *Func) BenchmarkName() string {
+	return f.prefixedName("Benchmark")
+}
+
+//FuzzName returns a name of the fuzz test
+func (f *Func) FuzzName() string {
+	return f.prefixedName("Fuzz")
+}
+
+//ExampleName returns a name of the example following the godoc
+//conventions, i.e. "ExampleT_Method" for the method
+func (f *Func) ExampleName() string {
+	return f.prefixedName("Example")
+}
+
+//prefixedName returns a name of the test function with the given prefix, i.e.
+//"TestT_Method" for the method, "Test_unexported" and "TestExported" for the functions
+func (f *Func) prefixedName(prefix string) string {
+	name := prefix
 	if f.IsMethod() {
-		recvType := f.ReceiverType()
-		if star, ok := recvType.(*ast.StarExpr); ok {
-			name += star.X.(*ast.Ident).String()
-		} else {
-			name += recvType.(*ast.Ident).String()
-		}
-		name += "_"
+		name += f.ReceiverTypeName() + "_"
 	} else if !f.Signature.Name.IsExported() {
 		name += "_"
 	}
@@ -156,6 +215,12 @@ func (f *Func) TestName() string {
 	return name + f.Signature.Name.String()
 }
 
+//ReceiverTypeName returns a name of the method receiver's base type
+//without a pointer, i.e. "T" for both "T" and "*T" receivers
+func (f *Func) ReceiverTypeName() string {
+	return baseTypeName(f.ReceiverType())
+}
+
 //IsMethod returns true if the function is a method
 func (f *Func) IsMethod() bool {
 	return f.Signature.Recv != nil
@@ -169,13 +234,20 @@ func (f *Func) ReceiverType() ast.Expr {
 	return f.Signature.Recv.List[0].Type
 }
 
-//ReturnsError returns true if the function's last param's type is error
+//ReturnsError returns true if the function's last param's type is error.
+//If the type information is available the type must be exactly the error
+//type (or its alias), so the results of the types implementing error,
+//i.e. *MyError, are treated as the regular results
 func (f *Func) ReturnsError() bool {
 	lastResult := f.LastResult()
 	if lastResult == nil {
 		return false
 	}
 
+	if results := f.ResultTypes(); len(results) > 0 {
+		return types.Identical(results[len(results)-1], types.Universe.Lookup("error").Type())
+	}
+
 	ident, ok := lastResult.Type.(*ast.Ident)
 	return ok && ident.Name == "error"
 }
@@ -215,3 +287,106 @@ func (f *Func) IsVariadic() bool {
 
 	return isVariadic
 }
+
+//IsTyped returns true if the type information is available for the function
+func (f *Func) IsTyped() bool {
+	return f.Object != nil
+}
+
+//ParamTypes returns a list of the resolved types of the function params
+//or nil if the type information is not available.
+//The type of the variadic param is a slice
+func (f *Func) ParamTypes() []types.Type {
+	if f.Object == nil {
+		return nil
+	}
+
+	return tupleTypes(f.Object.Type().(*types.Signature).Params())
+}
+
+//ResultTypes returns a list of the resolved types of the function results
+//or nil if the type information is not available
+func (f *Func) ResultTypes() []types.Type {
+	if f.Object == nil {
+		return nil
+	}
+
+	return tupleTypes(f.Object.Type().(*types.Signature).Results())
+}
+
+//Implements returns true if the type t implements the interface iface.
+//Interface can be "error", a name of the interface declared in the source
+//package or a name qualified with the imported package name or path, i.e. "io.Reader"
+func (f *Func) Implements(t types.Type, iface string) bool {
+	if f.Object == nil || t == nil {
+		return false
+	}
+
+	i := lookupInterface(f.Object.Pkg(), iface)
+	if i == nil {
+		return false
+	}
+
+	return types.Implements(t, i)
+}
+
+//IsComparable returns true if values of the type t can be compared with ==
+func (f *Func) IsComparable(t types.Type) bool {
+	return t != nil && types.Comparable(t)
+}
+
+//IsInterface returns true if t is an interface type
+func (f *Func) IsInterface(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	_, ok := t.Underlying().(*types.Interface)
+	return ok
+}
+
+//IsPointerToStruct returns true if t is a pointer to a struct type
+func (f *Func) IsPointerToStruct(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	ptr, ok := t.Underlying().(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	_, ok = ptr.Elem().Underlying().(*types.Struct)
+	return ok
+}
+
+//tupleTypes returns a list of types of the tuple variables
+func tupleTypes(t *types.Tuple) []types.Type {
+	list := make([]types.Type, 0, t.Len())
+	for i := 0; i < t.Len(); i++ {
+		list = append(list, t.At(i).Type())
+	}
+
+	return list
+}
+
+//baseTypeName returns a name of the type without a pointer,
+//parentheses, type arguments or a package qualifier
+func baseTypeName(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return baseTypeName(t.X)
+	case *ast.ParenExpr:
+		return baseTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return baseTypeName(t.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(t.X)
+	}
+
+	return ""
+}
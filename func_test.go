@@ -3,6 +3,7 @@ package gounit
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 	"reflect"
 	"testing"
 )
@@ -544,6 +545,16 @@ func TestFunc_TestName(t *testing.T) {
 			},
 			want1: "TestReceiver_Method",
 		},
+		{
+			name: "Parenthesized pointer method",
+			init: func(*testing.T) *Func {
+				return &Func{Signature: &ast.FuncDecl{
+					Name: &ast.Ident{Name: "Method"},
+					Recv: &ast.FieldList{List: []*ast.Field{{Type: &ast.ParenExpr{X: &ast.StarExpr{X: &ast.Ident{Name: "Receiver"}}}}}},
+				}}
+			},
+			want1: "TestReceiver_Method",
+		},
 	}
 
 	for _, tt := range tests {
@@ -937,3 +948,96 @@ func TestFunc_Name(t *testing.T) {
 		})
 	}
 }
+
+func TestFunc_Implements(t *testing.T) {
+	funcs := typedFuncs(t, `package typed
+
+	import "io"
+
+	type Reader struct{}
+
+	func (r *Reader) Read([]byte) (int, error) { return 0, nil }
+
+	func f(r *Reader, v Reader, e error) io.Reader { return r }`)
+
+	f := funcs[1]
+	params := f.ParamTypes()
+
+	tests := []struct {
+		name  string
+		t     types.Type
+		iface string
+
+		want1 bool
+	}{
+		{name: "pointer implements", t: params[0], iface: "io.Reader", want1: true},
+		{name: "value doesn't implement", t: params[1], iface: "io.Reader"},
+		{name: "error implements error", t: params[2], iface: "error", want1: true},
+		{name: "nil type", iface: "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := f.Implements(tt.t, tt.iface)
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.Implements got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+
+	if (&Func{}).Implements(params[0], "io.Reader") {
+		t.Errorf("untyped func must not implement anything")
+	}
+}
+
+func TestFunc_typePredicates(t *testing.T) {
+	funcs := typedFuncs(t, `package typed
+
+	type S struct{}
+
+	func f(p *S, s S, m map[int]int, i interface{}) {}`)
+
+	f := funcs[0]
+	params := f.ParamTypes()
+
+	if !f.IsPointerToStruct(params[0]) || f.IsPointerToStruct(params[1]) {
+		t.Errorf("unexpected IsPointerToStruct result")
+	}
+
+	if !f.IsComparable(params[1]) || f.IsComparable(params[2]) {
+		t.Errorf("unexpected IsComparable result")
+	}
+
+	if !f.IsInterface(params[3]) || f.IsInterface(params[0]) {
+		t.Errorf("unexpected IsInterface result")
+	}
+}
+
+func TestFunc_ReturnsError_typed(t *testing.T) {
+	funcs := typedFuncs(t, `package typed
+
+	type MyErr struct{}
+
+	func (e *MyErr) Error() string { return "" }
+
+	type Err interface { error }
+
+	func pointer() *MyErr { return nil }
+
+	func iface() Err { return nil }
+
+	func value() MyErr { return MyErr{} }
+
+	type Alias = error
+
+	func alias() (int, Alias) { return 0, nil }
+
+	func plain() (int, error) { return 0, nil }`)
+
+	for i, want := range []bool{false, false, false, false, true, true} {
+		if got := funcs[i].ReturnsError(); got != want {
+			t.Errorf("%s: Func.ReturnsError got1 = %v, want1: %v", funcs[i].Name(), got, want)
+		}
+	}
+}
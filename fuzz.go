@@ -0,0 +1,150 @@
+package gounit
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+//fuzzReserved are names declared by the fuzz template in the scope of the fuzz target
+var fuzzReserved = map[string]bool{"t": true, "receiver": true, "newReceiver": true}
+
+//IsFuzzable returns true if the function has params and all of them can be
+//passed to the fuzz target, i.e. they are strings, []byte, integers, floats or bools
+func (f *Func) IsFuzzable() bool {
+	if f.NumParams() == 0 || f.IsVariadic() {
+		return false
+	}
+
+	for _, p := range f.Signature.Type.Params.List {
+		if !isFuzzableType(p.Type) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//FuzzSeeds returns typed zero values of the params that can be added
+//to the seed corpus with f.Add, i.e. "", []byte(""), int64(0), false
+func (f *Func) FuzzSeeds(fs *token.FileSet) []string {
+	if f.Signature.Type.Params == nil {
+		return nil
+	}
+
+	seeds := []string{}
+	for _, p := range f.Signature.Type.Params.List {
+		seed := fuzzSeed(fs, p.Type)
+		for i := 0; i < numNames(p); i++ {
+			seeds = append(seeds, seed)
+		}
+	}
+
+	return seeds
+}
+
+//FuzzArgs returns names of the fuzz target params, blank and unnamed params
+//and the params clashing with the names declared by the fuzz template
+//(t, receiver and newReceiver) are renamed to argN
+func (f *Func) FuzzArgs() []string {
+	if f.Signature.Type.Params == nil {
+		return nil
+	}
+
+	taken := map[string]bool{}
+	for _, p := range f.Signature.Type.Params.List {
+		for _, n := range p.Names {
+			taken[n.Name] = true
+		}
+	}
+
+	var (
+		names []string
+		n     int
+	)
+
+	for _, p := range f.Signature.Type.Params.List {
+		for i := 0; i < numNames(p); i++ {
+			n++
+
+			name := "_"
+			if len(p.Names) > 0 {
+				name = p.Names[i].Name
+			}
+
+			if name == "_" || fuzzReserved[name] {
+				name = fmt.Sprintf("arg%d", n)
+				for taken[name] || fuzzReserved[name] {
+					name += "_"
+				}
+				taken[name] = true
+			}
+
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+//FuzzParams returns params of the fuzz target with their types,
+//params are named after FuzzArgs
+func (f *Func) FuzzParams(fs *token.FileSet) []string {
+	var (
+		names  = f.FuzzArgs()
+		params []string
+	)
+
+	for _, p := range f.Signature.Type.Params.List {
+		for i := 0; i < numNames(p); i++ {
+			params = append(params, names[len(params)]+" "+nodeToString(fs, p.Type))
+		}
+	}
+
+	return params
+}
+
+//numNames returns a number of the params declared by the field, unnamed param counts as one
+func numNames(field *ast.Field) int {
+	if len(field.Names) == 0 {
+		return 1
+	}
+
+	return len(field.Names)
+}
+
+//fuzzSeed returns a zero value of the fuzzable type, f.Add requires
+//the exact types of the params so untyped constants are converted
+func fuzzSeed(fs *token.FileSet, expr ast.Expr) string {
+	if _, isSlice := expr.(*ast.ArrayType); isSlice {
+		return `[]byte("")`
+	}
+
+	switch name := nodeToString(fs, expr); name {
+	case "string", "bool", "int":
+		zero, _ := predeclaredZero(name)
+		return zero
+	case "float64":
+		return "0.0"
+	default:
+		return name + "(0)"
+	}
+}
+
+//isFuzzableType returns true if the type is supported by the go fuzzing engine
+func isFuzzableType(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string", "bool", "byte", "rune", "float32", "float64",
+			"int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64":
+			return true
+		}
+	case *ast.ArrayType:
+		elt, ok := t.Elt.(*ast.Ident)
+		return t.Len == nil && ok && (elt.Name == "byte" || elt.Name == "uint8")
+	}
+
+	return false
+}
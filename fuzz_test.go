@@ -0,0 +1,190 @@
+package gounit
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseFunc(t *testing.T, fs *token.FileSet, decl string) *Func {
+	file, err := parser.ParseFile(fs, "file.go", "package p\n"+decl, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse function: %v", err)
+	}
+
+	return NewFunc(file.Decls[0].(*ast.FuncDecl))
+}
+
+func TestFunc_IsFuzzable(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+
+		want1 bool
+	}{
+		{
+			name:  "no params",
+			decl:  "func f() {}",
+			want1: false,
+		},
+		{
+			name:  "fuzzable params",
+			decl:  "func f(s string, b []byte, i int64, u uint8, r rune, fl float32, ok bool) {}",
+			want1: true,
+		},
+		{
+			name:  "method",
+			decl:  "func (t *T) f(b []uint8) {}",
+			want1: true,
+		},
+		{
+			name:  "variadic",
+			decl:  "func f(s ...string) {}",
+			want1: false,
+		},
+		{
+			name:  "array",
+			decl:  "func f(b [4]byte) {}",
+			want1: false,
+		},
+		{
+			name:  "named type",
+			decl:  "func f(s string, id ID) {}",
+			want1: false,
+		},
+		{
+			name:  "complex",
+			decl:  "func f(c complex128) {}",
+			want1: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := parseFunc(t, token.NewFileSet(), tt.decl).IsFuzzable()
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.IsFuzzable got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestFunc_FuzzSeeds(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+
+		want1 string
+	}{
+		{
+			name:  "no params",
+			decl:  "func f() {}",
+			want1: "",
+		},
+		{
+			name:  "typed seeds",
+			decl:  "func f(s, s2 string, b []byte, i int, i64 int64, r rune, f float64, f32 float32, ok bool) {}",
+			want1: `"", "", []byte(""), 0, int64(0), rune(0), 0.0, float32(0), false`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := token.NewFileSet()
+			got1 := strings.Join(parseFunc(t, fs, tt.decl).FuzzSeeds(fs), ", ")
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.FuzzSeeds got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestFunc_FuzzArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+
+		want1 []string
+		want2 []string
+	}{
+		{
+			name:  "named params",
+			decl:  "func f(s string, n int) {}",
+			want1: []string{"s", "n"},
+			want2: []string{"s string", "n int"},
+		},
+		{
+			name:  "blank and unnamed params",
+			decl:  "func f(_ string, x int) {}",
+			want1: []string{"arg1", "x"},
+			want2: []string{"arg1 string", "x int"},
+		},
+		{
+			name:  "unnamed params",
+			decl:  "func f(string, []byte) {}",
+			want1: []string{"arg1", "arg2"},
+			want2: []string{"arg1 string", "arg2 []byte"},
+		},
+		{
+			name:  "params clashing with the template names",
+			decl:  "func f(t, arg1 string, receiver bool) {}",
+			want1: []string{"arg1_", "arg1", "arg3"},
+			want2: []string{"arg1_ string", "arg1 string", "arg3 bool"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := token.NewFileSet()
+			f := parseFunc(t, fs, tt.decl)
+
+			if got1 := f.FuzzArgs(); !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("Func.FuzzArgs got1 = %v, want1: %v", got1, tt.want1)
+			}
+
+			if got2 := f.FuzzParams(fs); !reflect.DeepEqual(got2, tt.want2) {
+				t.Errorf("Func.FuzzParams got2 = %v, want2: %v", got2, tt.want2)
+			}
+
+			if got3 := f.FuzzSeeds(fs); len(got3) != len(tt.want1) {
+				t.Errorf("Func.FuzzSeeds got3 = %v, want %d seeds", got3, len(tt.want1))
+			}
+		})
+	}
+}
+
+func TestNewGenerator_fuzzTargetsCompile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_fuzz")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := []templateCase{
+		{"generic function", "func G[T any](s string) T { var zero T; return zero }"},
+		{"param named t", "func Trim(t string, n int) string { return t }"},
+		{"blank param", "func Blank(_ string, x int) int { return x }"},
+		{"unnamed param", "func Unnamed(string) bool { return true }"},
+		{"method with clashing params", "type S struct{}\n\nfunc (s *S) Do(receiver string, newReceiver int) {}"},
+		{"method of generic type", "type Stack[T comparable] struct{ items []T }\n\nfunc (s *Stack[T]) Len(t int) int { return t }"},
+	}
+
+	fs := token.NewFileSet()
+	imp := importer.ForCompiler(fs, "source", nil)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := checkCase(fs, imp, dir, "{{ $func := .Func }}", KindFuzz, c); err != nil {
+				t.Errorf("generated fuzz target doesn't compile: %v", err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io"
 	"os"
 	"path/filepath"
@@ -29,6 +30,11 @@ var (
 	ErrFixImports            = GenericError("failed to fix imports: %v")
 	ErrWriteTest             = GenericError("failed to write generated test: %v")
 	ErrInvalidTestTemplate   = GenericError("invalid test template: %v")
+	ErrImportPath            = GenericError("failed to resolve package import path: %v")
+	ErrUpdateTest            = GenericError("failed to update test: %v")
+	ErrNotExternalPackage    = GenericError("output file belongs to package %s rather than to the external test package")
+	ErrUnknownKind           = GenericError("unknown kind of the generated functions: %s")
+	ErrTemplateDirective     = GenericError("%s: failed to load template %q: %v")
 )
 
 type Options struct {
@@ -39,63 +45,179 @@ type Options struct {
 	Comment      string
 	Template     string
 	TemplateName string
+	Concurrency  int
 	All          bool
-	UseJSON      bool
-	UseStdin     bool
-	UseStdout    bool
+	Diff         bool
+	DryRun       bool
+	Update       bool
+
+	//Functions are either names or patterns of the function names, see MatchPattern.
+	//Exported, Unexported, Methods, Funcs and ReceiverType options filter
+	//the functions selected by the All, Lines and Functions options
+	Exported     bool
+	Unexported   bool
+	Methods      bool
+	Funcs        bool
+	ReceiverType string
+
+	//Kind is a kind of the generated functions, KindTest is used by default
+	Kind Kind
+
+	//Naming is a naming scheme of the tests, DefaultNaming is used if it's empty
+	Naming string
+
+	//CoverProfile enables the coverage mode: tests are generated for the functions
+	//that have no covered statements or whose coverage is below the CoverThreshold
+	//percentage regardless of the existing tests
+	CoverProfile   *CoverProfile
+	CoverThreshold float64
+
+	//LoadTemplate returns the contents of the template by its name,
+	//it's used to load templates set with //gounit:template directive
+	LoadTemplate func(name string) (string, error)
+
+	//ExternalPackage enables generation of the black-box tests in the
+	//external test package, i.e. "package foo_test"
+	ExternalPackage bool
+
+	TypeCheck bool
+	UseJSON   bool
+	UseStdin  bool
+	UseStdout bool
 }
 
 //Generator is used to generate a test stub for function Func
 type Generator struct {
 	fs             *token.FileSet
 	funcs          []*Func
+	skipped        []*Func
+	updates        []testUpdate
 	imports        []*ast.ImportSpec
 	pkg            string
 	opt            Options
 	buf            *bytes.Buffer
 	headerTemplate *template.Template
 	testTemplate   *template.Template
+
+	//templates are set with //gounit:template directive
+	templates map[string]*template.Template
 }
 
 //NewGenerator returns a pointer to Generator
 func NewGenerator(opt Options, src, testSrc io.Reader) (*Generator, error) {
 	fs := token.NewFileSet()
-	file, err := parser.ParseFile(fs, opt.InputFile, src, 0)
+	file, err := parser.ParseFile(fs, opt.InputFile, src, parser.ParseComments)
 
 	srcPackageName := file.Name.String()
 	if srcPackageName == "" {
 		return nil, ErrFailedToParseInFile.Format(err)
 	}
 
-	funcs := findFunctions(file.Decls, func(fd *ast.FuncDecl) bool {
-		if opt.All {
-			return true
-		}
+	kind := opt.Kind
+	if kind == "" {
+		kind = KindTest
+	}
 
-		for _, l := range opt.Lines {
-			if l == fs.Position(fd.Pos()).Line {
-				return true
-			}
-		}
+	if _, ok := builtinTemplates[kind]; !ok && kind != KindTest {
+		return nil, ErrUnknownKind.Format(kind)
+	}
 
-		for _, f := range opt.Functions {
-			if f == fd.Name.Name {
-				return true
-			}
+	if opt.CoverProfile != nil && kind != KindTest {
+		return nil, ErrCoverProfileKind.Format(kind)
+	}
+
+	var naming *NamingScheme
+	if opt.Naming != "" {
+		if naming, err = NewNamingScheme(opt.Naming); err != nil {
+			return nil, err
 		}
+	}
 
-		return false
+	selected, err := newSelector(opt, func(f *Func) int { return fs.Position(f.Signature.Pos()).Line })
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := findFunctions(file.Decls, func(fd *ast.FuncDecl) bool {
+		return selected(NewFunc(fd))
 	})
 
 	if len(funcs) == 0 {
 		return nil, ErrFuncNotFound
 	}
 
+	//the rest of the source package files are used to look up
+	//type declarations and constructors of the method receivers
+	pkgFiles := []*ast.File{file}
+	if opt.InputFile != "" {
+		pkgFiles = append(pkgFiles, parsePackageFiles(fs, srcPackageName, filepath.Dir(opt.InputFile), filepath.Base(opt.InputFile))...)
+	}
+
+	typeSpecs := map[string]*ast.TypeSpec{}
+	for i := len(pkgFiles) - 1; i >= 0; i-- {
+		for name, spec := range findTypeSpecs(pkgFiles[i].Decls) {
+			typeSpecs[name] = spec
+		}
+	}
+
+	var candidates []*Func
+	for _, f := range pkgFiles {
+		candidates = append(candidates, findFunctions(f.Decls, func(fd *ast.FuncDecl) bool {
+			return fd.Recv == nil
+		})...)
+	}
+
+	var info *types.Info
+	if opt.TypeCheck {
+		_, info = typeCheck(fs, pkgFiles)
+	}
+
+	srcNames := declaredNames(pkgFiles)
+
+	for _, f := range append(funcs, candidates...) {
+		f.typeSpecs = typeSpecs
+		f.naming = naming
+		f.declared = srcNames
+		if info != nil {
+			f.Object, _ = info.Defs[f.Signature.Name].(*types.Func)
+		}
+	}
+
+	for _, f := range funcs {
+		if f.IsMethod() {
+			f.Constructors = findConstructors(f, candidates)
+		}
+	}
+
 	var (
 		buf            = bytes.NewBuffer([]byte{})
 		dstPackageName = srcPackageName
+		testFiles      []*ast.File
+		updates        []testUpdate
+
+		//declared are names of the functions declared in the test files
+		declared = map[string]bool{}
 	)
 
+	//in the coverage mode existing tests don't matter unless their names clash
+	missing := func(file *ast.File, funcs []*Func) []*Func {
+		if opt.CoverProfile == nil {
+			return findMissing(file, funcs, kind)
+		}
+
+		for name := range findExistingTests(file).funcs {
+			declared[name] = true
+		}
+
+		return funcs
+	}
+
+	if opt.CoverProfile != nil {
+		if funcs, err = opt.CoverProfile.uncovered(fs, opt.InputFile, funcs, opt.CoverThreshold); err != nil {
+			return nil, err
+		}
+	}
+
 	if testSrc != nil {
 		tr := io.TeeReader(testSrc, buf)
 
@@ -107,12 +229,25 @@ func NewGenerator(opt Options, src, testSrc io.Reader) (*Generator, error) {
 
 		//using package name from the destination file since it can be a *_test package
 		dstPackageName = file.Name.String()
-		funcs = findMissingTests(file, funcs)
+
+		if opt.Update && kind == KindTest {
+			//types of the source package are qualified in the external test package
+			var qualifier string
+			if dstPackageName == srcPackageName+"_test" {
+				qualifier = srcPackageName + "."
+			}
+
+			updates = findStaleTests(fs, file, funcs, qualifier)
+		}
+
+		funcs = missing(file, funcs)
+		testFiles = append(testFiles, file)
 	}
 
 	//this filter leaves only test files so we can ignore syntax errors in the tested code
 	//but we still have to fail when test files contain syntax errors because it's not possible
 	//to identify missing tests in such case
+	outputDir := filepath.Dir(opt.OutputFile)
 	filter := func(fi os.FileInfo) bool {
 		if fi.IsDir() {
 			return false
@@ -122,31 +257,142 @@ func NewGenerator(opt Options, src, testSrc io.Reader) (*Generator, error) {
 			return true
 		}
 
-		f, err := os.Open(fi.Name())
+		f, err := os.Open(filepath.Join(outputDir, fi.Name()))
 		if err != nil {
 			return false
 		}
 		defer f.Close()
 
-		astFile, _ := parser.ParseFile(fs, fi.Name(), f, parser.PackageClauseOnly)
+		astFile, err := parser.ParseFile(fs, fi.Name(), f, parser.PackageClauseOnly)
+		if err != nil {
+			return false
+		}
 
 		return astFile.Name.String() == srcPackageName+"_test"
 	}
 
-	packages, err := parser.ParseDir(fs, filepath.Dir(opt.OutputFile), filter, 0)
+	packages, err := parser.ParseDir(fs, outputDir, filter, 0)
 	if err != nil {
 		return nil, ErrFailedToParseOutFile.Format(err)
 	}
 
 	for _, pkg := range packages {
 		for _, file := range pkg.Files {
-			funcs = findMissingTests(file, funcs)
+			funcs = missing(file, funcs)
+			testFiles = append(testFiles, file)
+		}
+	}
+
+	funcs = renameExisting(funcs, declared)
+
+	//types declared in the test files, i.e. mocks generated by MockGenerator
+	testTypes := map[string]bool{}
+	for _, file := range testFiles {
+		for name := range findTypeSpecs(file.Decls) {
+			testTypes[name] = true
+		}
+	}
+
+	for _, f := range funcs {
+		f.testTypes = testTypes
+	}
+
+	importSpecs := file.Imports
+
+	var skipped []*Func
+	if external := srcPackageName + "_test"; opt.ExternalPackage || dstPackageName == external {
+		if testSrc == nil {
+			dstPackageName = external
+		}
+
+		if dstPackageName != external {
+			return nil, ErrNotExternalPackage.Format(dstPackageName)
+		}
+
+		path, err := importPath(filepath.Dir(opt.InputFile))
+		if err != nil {
+			return nil, err
+		}
+
+		importSpecs = append(append([]*ast.ImportSpec{}, importSpecs...), importSpec(path))
+
+		funcs, skipped = externalize(funcs, srcPackageName)
+
+		var stale []testUpdate
+		for _, u := range updates {
+			if exported, _ := externalize([]*Func{u.f}, srcPackageName); len(exported) > 0 {
+				stale = append(stale, u)
+			}
 		}
+		updates = stale
 	}
 
-	testTemplate, err := template.New("test").Funcs(templateHelpers(fs)).Parse(opt.Template)
+	funcs, skipped = skipFuncs(funcs, skipped, func(f *Func) bool { return !f.IsSkipped() })
+
+	var notSkipped []testUpdate
+	for _, u := range updates {
+		if !u.f.IsSkipped() {
+			notSkipped = append(notSkipped, u)
+		}
+	}
+	updates = notSkipped
+
+	switch kind {
+	case KindFuzz:
+		funcs, skipped = skipFuncs(funcs, skipped, (*Func).IsFuzzable)
+	case KindExample:
+		funcs, skipped = skipFuncs(funcs, skipped, (*Func).IsExported)
+	}
+
+	testTemplate, meta, err := parseTemplate(fs, opt.Template)
 	if err != nil {
-		return nil, ErrInvalidTestTemplate.Format(err)
+		return nil, err
+	}
+
+	//test template can define its own header, i.e. {{define "header"}}...{{end}}
+	header := testTemplate.Lookup("header")
+	if header == nil {
+		header = template.Must(template.New("header").Funcs(templateHelpers(fs)).Parse(headerTemplate))
+	}
+
+	if testTemplate, err = kindTemplate(testTemplate, kind); err != nil {
+		return nil, err
+	}
+
+	//templates set with //gounit:template directive
+	templates := map[string]*template.Template{}
+	for _, f := range append(append([]*Func{}, funcs...), updatedFuncs(updates)...) {
+		name, ok := f.Directive("template")
+		if !ok || templates[name] != nil {
+			continue
+		}
+
+		if opt.LoadTemplate == nil {
+			return nil, ErrTemplateDirective.Format(f.Name(), name, "templates can't be loaded")
+		}
+
+		contents, err := opt.LoadTemplate(name)
+		if err != nil {
+			return nil, ErrTemplateDirective.Format(f.Name(), name, err)
+		}
+
+		t, m, err := parseTemplate(fs, contents)
+		if err != nil {
+			return nil, ErrTemplateDirective.Format(f.Name(), name, err)
+		}
+
+		if templates[name], err = kindTemplate(t, kind); err != nil {
+			return nil, ErrTemplateDirective.Format(f.Name(), name, err)
+		}
+
+		meta.Imports = append(meta.Imports, m.Imports...)
+	}
+
+	//imports required by the templates are added to the imports of the source file
+	for _, path := range meta.Imports {
+		if !hasImport(importSpecs, path) {
+			importSpecs = append(append([]*ast.ImportSpec{}, importSpecs...), importSpec(path))
+		}
 	}
 
 	return &Generator{
@@ -154,18 +400,59 @@ func NewGenerator(opt Options, src, testSrc io.Reader) (*Generator, error) {
 		opt:            opt,
 		fs:             fs,
 		funcs:          funcs,
-		imports:        file.Imports,
+		skipped:        skipped,
+		updates:        updates,
+		imports:        importSpecs,
 		pkg:            dstPackageName,
-		headerTemplate: template.Must(template.New("header").Funcs(templateHelpers(fs)).Parse(headerTemplate)),
+		headerTemplate: header,
 		testTemplate:   testTemplate,
+		templates:      templates,
 	}, nil
 }
 
+//parseTemplate parses the contents of the template file
+func parseTemplate(fs *token.FileSet, contents string) (*template.Template, TemplateMeta, error) {
+	meta, body, err := ParseTemplate(contents)
+	if err != nil {
+		return nil, meta, ErrInvalidTestTemplate.Format(err)
+	}
+
+	t, err := template.New("test").Funcs(templateHelpers(fs)).Parse(body)
+	if err != nil {
+		return nil, meta, ErrInvalidTestTemplate.Format(err)
+	}
+
+	return t, meta, nil
+}
+
+//kindTemplate returns a template of the kind. Templates of the other kinds can be
+//defined in the test template, i.e. {{define "bench"}}...{{end}}, otherwise the
+//built-in template is used
+func kindTemplate(t *template.Template, kind Kind) (*template.Template, error) {
+	if kind == KindTest {
+		return t, nil
+	}
+
+	if t.Lookup(string(kind)) == nil {
+		if _, err := t.New(string(kind)).Parse(builtinTemplates[kind]); err != nil {
+			return nil, ErrInvalidTestTemplate.Format(err)
+		}
+	}
+
+	return t.Lookup(string(kind)), nil
+}
+
 func (g *Generator) Write(w io.Writer) error {
-	if len(g.funcs) == 0 {
+	if len(g.funcs) == 0 && len(g.updates) == 0 {
 		return nil
 	}
 
+	if len(g.updates) > 0 {
+		if err := g.applyUpdates(); err != nil {
+			return ErrUpdateTest.Format(err)
+		}
+	}
+
 	if g.buf.Len() == 0 {
 		if err := g.WriteHeader(g.buf); err != nil {
 			return ErrGenerateHeader.Format(err)
@@ -188,11 +475,32 @@ func (g *Generator) Write(w io.Writer) error {
 	return nil
 }
 
+//Funcs returns a list of functions that don't have tests yet
+func (g *Generator) Funcs() []*Func {
+	return g.funcs
+}
+
+//Updated returns a list of functions whose existing tests don't match their
+//signatures and are updated by Write (see Options.Update)
+func (g *Generator) Updated() []*Func {
+	return updatedFuncs(g.updates)
+}
+
+//Skipped returns a list of the functions marked with //gounit:skip directive,
+//unexported functions and functions referencing unexported types that can't
+//be tested from the external test package (see Options.ExternalPackage and
+//Func.UnexportedTypes), functions that have no examples and the functions
+//that can't be fuzzed (see Func.IsFuzzable)
+func (g *Generator) Skipped() []*Func {
+	return g.skipped
+}
+
 func (g *Generator) Source() string {
 	return g.buf.String()
 }
 
-//WriteHeader writes a package name and import specs
+//WriteHeader writes a package name and import specs using the header
+//template defined in the test template or the built-in one
 func (g *Generator) WriteHeader(w io.Writer) error {
 	return g.headerTemplate.Execute(w, struct {
 		Imports []*ast.ImportSpec
@@ -206,22 +514,36 @@ func (g *Generator) WriteHeader(w io.Writer) error {
 //WriteTests writes test stubs for every function that don't have test yet
 func (g *Generator) WriteTests(w io.Writer) error {
 	for _, f := range g.funcs {
-		err := g.testTemplate.Execute(w, struct {
-			Func    *Func
-			Comment string
-		}{
-			Func:    f,
-			Comment: g.opt.Comment,
-		})
-
-		if err != nil {
-			return fmt.Errorf("failed to write test: %v", err)
+		if err := g.writeTest(w, f); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+//writeTest writes a test stub for the function
+func (g *Generator) writeTest(w io.Writer, f *Func) error {
+	t := g.testTemplate
+	if name, ok := f.Directive("template"); ok {
+		t = g.templates[name]
+	}
+
+	err := t.Execute(w, struct {
+		Func    *Func
+		Comment string
+	}{
+		Func:    f,
+		Comment: g.opt.Comment,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to write test: %v", err)
+	}
+
+	return nil
+}
+
 var headerTemplate = `package {{.Package}}
 
 import(
@@ -229,3 +551,17 @@ import(
 	"reflect"{{range $import := .Imports}}
 	{{ast $import}}{{end}}
 )`
+
+//skipFuncs moves the functions that don't satisfy the predicate to the skipped ones
+func skipFuncs(funcs, skipped []*Func, predicate func(*Func) bool) ([]*Func, []*Func) {
+	var filtered []*Func
+	for _, f := range funcs {
+		if predicate(f) {
+			filtered = append(filtered, f)
+		} else {
+			skipped = append(skipped, f)
+		}
+	}
+
+	return filtered, skipped
+}
@@ -105,6 +105,53 @@ func TestGenerator_WriteHeader(t *testing.T) {
 	}
 }
 
+func TestNewGenerator_header(t *testing.T) {
+	const src = `package header
+
+import "io"
+
+func Copy(w io.Writer) {}
+`
+
+	tests := []struct {
+		name     string
+		template string
+
+		want1 string
+	}{
+		{
+			name:     "built-in header",
+			template: "{{ .Func.TestName }}",
+			want1:    "package header\n\nimport(\n\t\"testing\"\n\t\"reflect\"\n\t\"io\"\n)",
+		},
+		{
+			name:     "header defined in the template",
+			template: "{{ define \"header\" }}//go:build unit\n\n//Package {{ .Package }} is tested here\npackage {{ .Package }}{{ end }}{{ .Func.TestName }}",
+			want1:    "//go:build unit\n\n//Package header is tested here\npackage header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := Options{All: true, InputFile: "header.go", OutputFile: "header_test.go", Template: tt.template}
+
+			g, err := NewGenerator(opt, strings.NewReader(src), nil)
+			if err != nil {
+				t.Fatalf("NewGenerator: %v", err)
+			}
+
+			buf := bytes.NewBuffer([]byte{})
+			if err := g.WriteHeader(buf); err != nil {
+				t.Fatalf("Generator.WriteHeader: %v", err)
+			}
+
+			if got1 := buf.String(); got1 != tt.want1 {
+				t.Errorf("Generator.WriteHeader got1 = %q, want1: %q", got1, tt.want1)
+			}
+		})
+	}
+}
+
 func TestGenerator_WriteTests(t *testing.T) {
 	type args struct {
 		w io.Writer
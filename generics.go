@@ -0,0 +1,303 @@
+package gounit
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+//typeParam is a type parameter of a generic function or a generic method receiver
+type typeParam struct {
+	name string
+
+	//constraint is nil when the declaration of the receiver type is not available
+	constraint ast.Expr
+
+	//typed is nil unless the type information is available
+	typed *types.TypeParam
+}
+
+//IsGeneric returns true if the function has type parameters or
+//if it's a method of a generic type
+func (f *Func) IsGeneric() bool {
+	return len(f.typeParams()) > 0
+}
+
+//TypeParamsNames returns a list of names of the function's type parameters.
+//For the methods of generic types names are taken from the receiver, i.e. ["K", "V"] for
+//the receiver "c *Cache[K, V]"
+func (f *Func) TypeParamsNames() []string {
+	var names []string
+	for _, tp := range f.typeParams() {
+		names = append(names, tp.name)
+	}
+
+	return names
+}
+
+//TypeParams returns a list of the function's type parameters with their constraints,
+//i.e. ["K comparable", "V any"]. Constraint of the receiver's type parameter is "any"
+//if the receiver type declaration can't be found in the source file
+func (f *Func) TypeParams(fs *token.FileSet) []string {
+	var params []string
+	for _, tp := range f.typeParams() {
+		constraint := "any"
+		switch {
+		case tp.constraint != nil:
+			constraint = nodeToString(fs, tp.constraint)
+		case tp.typed != nil:
+			constraint = types.TypeString(tp.typed.Constraint(), f.qualifier())
+		}
+
+		params = append(params, tp.name+" "+constraint)
+	}
+
+	return params
+}
+
+//TypeParamsList returns a list of the type parameters to instantiate a generic
+//function with, i.e. "[K, V]". It returns an empty string for non-generic
+//functions and for methods since they're instantiated by their receivers
+func (f *Func) TypeParamsList() string {
+	if f.IsMethod() || !f.IsGeneric() {
+		return ""
+	}
+
+	return "[" + strings.Join(f.TypeParamsNames(), ", ") + "]"
+}
+
+//TypeAliases returns a list of type alias specs that bind every type parameter of
+//the function to a placeholder type argument that satisfies its constraint, i.e. ["K = any", "N = int"].
+//Aliases are ordered so that every alias is declared before it's referenced by other aliases
+func (f *Func) TypeAliases(fs *token.FileSet) []string {
+	var (
+		params       = f.typeParams()
+		placeholders = map[string]string{}
+	)
+
+	for _, tp := range params {
+		placeholder := "any"
+		switch {
+		case tp.typed != nil:
+			placeholder = typedPlaceholder(tp.typed.Constraint(), f.qualifier())
+		case tp.constraint != nil:
+			placeholder = f.placeholder(fs, tp.constraint)
+		}
+
+		placeholders[tp.name] = placeholder
+	}
+
+	var (
+		aliases  []string
+		declared = map[string]bool{}
+	)
+
+	for len(aliases) < len(params) {
+		progress := false
+		for _, tp := range params {
+			if declared[tp.name] || !dependenciesDeclared(placeholders[tp.name], placeholders, declared) {
+				continue
+			}
+
+			aliases = append(aliases, tp.name+" = "+placeholders[tp.name])
+			declared[tp.name] = true
+			progress = true
+		}
+
+		//cyclic references, i.e. [T ~[]U, U ~[]T], can't be ordered
+		if !progress {
+			for _, tp := range params {
+				if !declared[tp.name] {
+					aliases = append(aliases, tp.name+" = any")
+				}
+			}
+			break
+		}
+	}
+
+	return aliases
+}
+
+//dependenciesDeclared returns true if all type parameters referenced by
+//the type expression are already declared
+func dependenciesDeclared(expr string, params map[string]string, declared map[string]bool) bool {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return true
+	}
+
+	ok := true
+	ast.Inspect(e, func(n ast.Node) bool {
+		if ident, isIdent := n.(*ast.Ident); isIdent {
+			if _, isParam := params[ident.Name]; isParam && !declared[ident.Name] {
+				ok = false
+			}
+		}
+		return ok
+	})
+
+	return ok
+}
+
+//typeParams returns type parameters of the function or type parameters of the
+//method receiver
+func (f *Func) typeParams() []typeParam {
+	var (
+		params []typeParam
+		typed  *types.TypeParamList
+	)
+
+	if f.Object != nil {
+		sig := f.Object.Type().(*types.Signature)
+		if typed = sig.TypeParams(); f.IsMethod() {
+			typed = sig.RecvTypeParams()
+		}
+	}
+
+	if !f.IsMethod() {
+		if f.Signature.Type == nil || f.Signature.Type.TypeParams == nil {
+			return nil
+		}
+
+		for _, field := range f.Signature.Type.TypeParams.List {
+			for _, name := range field.Names {
+				params = append(params, typeParam{name: name.Name, constraint: field.Type})
+			}
+		}
+	} else {
+		var decl []*ast.Field
+		if spec := f.typeSpecs[f.ReceiverTypeName()]; spec != nil && spec.TypeParams != nil {
+			decl = spec.TypeParams.List
+		}
+
+		for i, name := range receiverTypeParams(f.ReceiverType()) {
+			params = append(params, typeParam{name: name, constraint: fieldTypeAt(decl, i)})
+		}
+	}
+
+	if typed != nil && typed.Len() == len(params) {
+		for i := range params {
+			params[i].typed = typed.At(i)
+		}
+	}
+
+	return params
+}
+
+//placeholder returns a type that satisfies the constraint expression
+func (f *Func) placeholder(fs *token.FileSet, constraint ast.Expr) string {
+	switch c := constraint.(type) {
+	case *ast.Ident:
+		if isPredeclaredType(c.Name) && c.Name != "any" && c.Name != "comparable" {
+			return c.Name
+		}
+
+		//constraint declared in the source file
+		if spec := f.typeSpecs[c.Name]; spec != nil {
+			return f.placeholder(fs, spec.Type)
+		}
+	case *ast.BinaryExpr:
+		if c.Op == token.OR {
+			return f.placeholder(fs, c.X)
+		}
+	case *ast.UnaryExpr:
+		if c.Op == token.TILDE {
+			return nodeToString(fs, c.X)
+		}
+	case *ast.InterfaceType:
+		for _, field := range c.Methods.List {
+			if len(field.Names) == 0 {
+				return f.placeholder(fs, field.Type)
+			}
+		}
+	case *ast.SelectorExpr:
+		//constraint from another package, i.e. constraints.Ordered
+		return "any"
+	case *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.StarExpr, *ast.FuncType:
+		return nodeToString(fs, c)
+	}
+
+	return "any"
+}
+
+//typedPlaceholder returns a type that satisfies the constraint
+func typedPlaceholder(constraint types.Type, qf types.Qualifier) string {
+	iface, ok := constraint.Underlying().(*types.Interface)
+	if !ok {
+		return types.TypeString(constraint, qf)
+	}
+
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		switch embedded := iface.EmbeddedType(i).(type) {
+		case *types.Union:
+			if embedded.Len() > 0 {
+				return types.TypeString(embedded.Term(0).Type(), qf)
+			}
+		default:
+			if placeholder := typedPlaceholder(embedded, qf); placeholder != "any" {
+				return placeholder
+			}
+		}
+	}
+
+	return "any"
+}
+
+//qualifier returns a types.Qualifier that omits the name of the source package
+//unless the function is tested from the external test package
+func (f *Func) qualifier() types.Qualifier {
+	if f.Object == nil {
+		return nil
+	}
+
+	if f.pkg != "" {
+		return externalQualifier
+	}
+
+	return types.RelativeTo(f.Object.Pkg())
+}
+
+//receiverTypeParams returns a list of type parameters names of the method receiver
+func receiverTypeParams(recv ast.Expr) []string {
+	var indices []ast.Expr
+
+	switch t := recv.(type) {
+	case *ast.StarExpr:
+		return receiverTypeParams(t.X)
+	case *ast.ParenExpr:
+		return receiverTypeParams(t.X)
+	case *ast.IndexExpr:
+		indices = []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		indices = t.Indices
+	}
+
+	var names []string
+	for _, index := range indices {
+		if ident, ok := index.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+	}
+
+	return names
+}
+
+//fieldTypeAt returns a type of the i-th name declared in the list of fields
+func fieldTypeAt(fields []*ast.Field, i int) ast.Expr {
+	for _, field := range fields {
+		if i < len(field.Names) {
+			return field.Type
+		}
+		i -= len(field.Names)
+	}
+
+	return nil
+}
+
+//isPredeclaredType returns true if the name is a name of the predeclared type
+func isPredeclaredType(name string) bool {
+	obj, ok := types.Universe.Lookup(name).(*types.TypeName)
+	return ok && obj != nil
+}
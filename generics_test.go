@@ -0,0 +1,143 @@
+package gounit
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+const genericSource = `package generic
+
+type Number interface{ ~int | ~float64 }
+
+type Cache[K comparable, V any] struct{}
+
+func (c *Cache[K, V]) Get(k K) V { var v V; return v }
+
+func Sum[N Number](xs ...N) N { var n N; return n }
+
+func Keys[M ~map[K]V, K comparable, V any](m M) []K { return nil }
+
+func Plain() {}`
+
+//parseFuncs parses the source and returns all functions declared in it
+func parseFuncs(t *testing.T, src string) (*token.FileSet, []*Func) {
+	fs := token.NewFileSet()
+	file, err := parser.ParseFile(fs, "file.go", src, 0)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+
+	funcs := findFunctions(file.Decls, func(*ast.FuncDecl) bool { return true })
+	typeSpecs := findTypeSpecs(file.Decls)
+	for _, f := range funcs {
+		f.typeSpecs = typeSpecs
+	}
+
+	return fs, funcs
+}
+
+func TestFunc_TypeParams(t *testing.T) {
+	fs, funcs := parseFuncs(t, genericSource)
+
+	tests := []struct {
+		name string
+		f    *Func
+
+		wantName      string
+		wantParams    []string
+		wantAliases   []string
+		wantParamList string
+	}{
+		{
+			name:        "method of generic type",
+			f:           funcs[0],
+			wantName:    "TestCache_Get",
+			wantParams:  []string{"K comparable", "V any"},
+			wantAliases: []string{"K = any", "V = any"},
+		},
+		{
+			name:          "constraint declared in the source file",
+			f:             funcs[1],
+			wantName:      "TestSum",
+			wantParams:    []string{"N Number"},
+			wantAliases:   []string{"N = int"},
+			wantParamList: "[N]",
+		},
+		{
+			name:          "dependent type params",
+			f:             funcs[2],
+			wantName:      "TestKeys",
+			wantParams:    []string{"M ~map[K]V", "K comparable", "V any"},
+			wantAliases:   []string{"K = any", "V = any", "M = map[K]V"},
+			wantParamList: "[M, K, V]",
+		},
+		{
+			name:     "not generic",
+			f:        funcs[3],
+			wantName: "TestPlain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.TestName(); got != tt.wantName {
+				t.Errorf("Func.TestName got1 = %v, want1: %v", got, tt.wantName)
+			}
+
+			if got := tt.f.TypeParams(fs); !reflect.DeepEqual(got, tt.wantParams) {
+				t.Errorf("Func.TypeParams got1 = %v, want1: %v", got, tt.wantParams)
+			}
+
+			if got := tt.f.TypeAliases(fs); !reflect.DeepEqual(got, tt.wantAliases) {
+				t.Errorf("Func.TypeAliases got1 = %v, want1: %v", got, tt.wantAliases)
+			}
+
+			if got := tt.f.TypeParamsList(); got != tt.wantParamList {
+				t.Errorf("Func.TypeParamsList got1 = %v, want1: %v", got, tt.wantParamList)
+			}
+
+			if got := tt.f.IsGeneric(); got != (len(tt.wantParams) > 0) {
+				t.Errorf("Func.IsGeneric got1 = %v", got)
+			}
+		})
+	}
+}
+
+func TestFunc_TypeAliases_typed(t *testing.T) {
+	funcs := typedFuncs(t, genericSource)
+
+	if got := funcs[1].TypeAliases(token.NewFileSet()); !reflect.DeepEqual(got, []string{"N = int"}) {
+		t.Errorf("Func.TypeAliases got1 = %v, want1: [N = int]", got)
+	}
+}
+
+func Test_receiverTypeParams(t *testing.T) {
+	tests := []struct {
+		name string
+		recv string
+
+		want1 []string
+	}{
+		{name: "not generic", recv: "*T"},
+		{name: "single param", recv: "*List[T]", want1: []string{"T"}},
+		{name: "multiple params", recv: "(Cache[K, V])", want1: []string{"K", "V"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tt.recv)
+			if err != nil {
+				t.Fatalf("failed to parse receiver: %v", err)
+			}
+
+			got1 := receiverTypeParams(expr)
+
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("receiverTypeParams got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
@@ -13,25 +13,23 @@ type matchFunc func(*ast.FuncDecl) bool
 
 //findMissingTests filters funcs slice and returns only those functions that don't have tests yet
 func findMissingTests(file *ast.File, funcs []*Func) []*Func {
-	tests := findFunctions(file.Decls, func(fd *ast.FuncDecl) bool {
-		for _, sourceFunc := range funcs {
-			f := NewFunc(fd)
-			if f.ReceiverType() == nil && f.Name() == sourceFunc.TestName() {
-				return true
-			}
-		}
-		return false
-	})
+	return findMissing(file, funcs, KindTest)
+}
+
+//findMissing filters funcs slice and returns only those functions that don't have
+//generated functions of the given kind yet, i.e. benchmarks. Tests named in the
+//common alternative styles, test methods of the testify suites and subtests
+//are recognized as well (see existingTests.covers)
+func findMissing(file *ast.File, funcs []*Func, kind Kind) []*Func {
+	tests := findExistingTests(file)
 
 	dontHaveTests := []*Func{}
 	for _, f := range funcs {
-		testIsFound := false
-		for _, test := range tests {
-			if test.Name() == f.TestName() {
-				testIsFound = true
-				break
-			}
+		testIsFound := tests.funcs[kind.FuncName(f)]
+		if kind == KindTest {
+			testIsFound = tests.covers(f)
 		}
+
 		if !testIsFound {
 			dontHaveTests = append(dontHaveTests, f)
 		}
@@ -53,6 +51,26 @@ func findFunctions(decls []ast.Decl, match matchFunc) []*Func {
 	return funcs
 }
 
+//findTypeSpecs returns all type declarations found in decls
+func findTypeSpecs(decls []ast.Decl) map[string]*ast.TypeSpec {
+	specs := map[string]*ast.TypeSpec{}
+
+	for _, decl := range decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				specs[ts.Name.Name] = ts
+			}
+		}
+	}
+
+	return specs
+}
+
 //nodeToString returns a string representation of an AST node
 //as it has in the original source code
 func nodeToString(fs *token.FileSet, n ast.Node) string {
@@ -74,6 +92,12 @@ func templateHelpers(fs *token.FileSet) template.FuncMap {
 		"results": func(f *Func) []string {
 			return f.Results(fs)
 		},
+		"typeParams": func(f *Func) []string {
+			return f.TypeParams(fs)
+		},
+		"typeAliases": func(f *Func) []string {
+			return f.TypeAliases(fs)
+		},
 		"receiver": func(f *Func) string {
 			if f.ReceiverType() == nil {
 				return ""
@@ -82,5 +106,52 @@ func templateHelpers(fs *token.FileSet) template.FuncMap {
 			return strings.Replace(nodeToString(fs, f.ReceiverType()), "*", "", -1) + "."
 		},
 		"want": func(s string) string { return strings.Replace(s, "got", "want", 1) },
+		"zero": func(f *Func, typ interface{}) string {
+			return f.ZeroValue(fs, typ)
+		},
+		"wantZero": func(f *Func, typ interface{}) string {
+			return f.WantZero(fs, typ)
+		},
+		"indent":    indent,
+		"fieldName": func(s string) string { return strings.Fields(s)[0] },
+		"construct": func(f *Func) string {
+			return f.ConstructReceiver(fs)
+		},
+		"seeds": func(f *Func) []string {
+			return f.FuzzSeeds(fs)
+		},
+		"fuzzParams": func(f *Func) []string {
+			return f.FuzzParams(fs)
+		},
+		"zeroArgs": func(f *Func) []string {
+			return f.ZeroArgs(fs)
+		},
 	}
 }
+
+//indent prefixes every line of the code but the first one with the prefix and the tabs
+//matching the nesting of the braces, it's used to render the code inside comments
+//where gofmt doesn't indent it
+func indent(prefix, code string) string {
+	lines := strings.Split(code, "\n")
+
+	depth := 0
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "}") && depth > 0 {
+			depth--
+		}
+
+		if i > 0 && line != "" {
+			line = prefix + strings.Repeat("\t", depth) + line
+		}
+
+		if strings.HasSuffix(line, "{") {
+			depth++
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
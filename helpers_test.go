@@ -116,6 +116,61 @@ func Test_findMissingTests(t *testing.T) {
 	}
 }
 
+func Test_findMissing(t *testing.T) {
+	const gofile = `package gofile
+
+	func Benchmark_function(b *testing.B) {}
+
+	func Test_tested(t *testing.T) {}
+
+	func TestTested(t *testing.T) {}
+
+	func TestT_Method(t *testing.T) {}`
+
+	fs := token.NewFileSet()
+	file, err := parser.ParseFile(fs, "file.go", []byte(gofile), 0)
+	if err != nil {
+		t.Fatalf("failed to parse file: %v", err)
+	}
+
+	var (
+		function = &Func{Signature: &ast.FuncDecl{Name: &ast.Ident{Name: "function"}}}
+		tested   = &Func{Signature: &ast.FuncDecl{Name: &ast.Ident{Name: "tested"}}}
+		method   = &Func{Signature: &ast.FuncDecl{
+			Name: &ast.Ident{Name: "method"},
+			Recv: &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: "T"}}}},
+		}}
+	)
+
+	tests := []struct {
+		name string
+		kind Kind
+
+		want1 []*Func
+	}{
+		{
+			name:  "tests",
+			kind:  KindTest,
+			want1: []*Func{function},
+		},
+		{
+			name:  "benchmarks",
+			kind:  KindBenchmark,
+			want1: []*Func{tested, method},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := findMissing(file, []*Func{function, tested, method}, tt.kind)
+
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("findMissing got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
 func Test_templateHelpers(t *testing.T) {
 	helpers := templateHelpers(token.NewFileSet())
 
@@ -173,6 +228,26 @@ func Test_templateHelpers(t *testing.T) {
 	if want != "want1" {
 		t.Errorf("unexpected want helper result: %s", want)
 	}
+
+	zeroHelper, ok := helpers["zero"].(func(*Func, interface{}) string)
+	if !ok {
+		t.Fatalf("unexpected zero helper type")
+	}
+
+	zero := zeroHelper(&Func{Signature: &ast.FuncDecl{}}, "got1 string")
+	if zero != `""` {
+		t.Errorf("unexpected zero helper result: %s", zero)
+	}
+
+	fieldNameHelper, ok := helpers["fieldName"].(func(string) string)
+	if !ok {
+		t.Fatalf("unexpected fieldName helper type")
+	}
+
+	fieldName := fieldNameHelper("want1 []string")
+	if fieldName != "want1" {
+		t.Errorf("unexpected fieldName helper result: %s", fieldName)
+	}
 }
 
 func Test_findFunctions(t *testing.T) {
@@ -210,3 +285,16 @@ func Test_findFunctions(t *testing.T) {
 		})
 	}
 }
+
+func Test_indent(t *testing.T) {
+	code := "r, err := NewT()\nif err != nil {\nt.Fatal(err)\n}\n\nreturn r"
+	want := "r, err := NewT()\n\t\tif err != nil {\n\t\t\tt.Fatal(err)\n\t\t}\n\n\t\treturn r"
+
+	if got := indent("\t\t", code); got != want {
+		t.Errorf("indent got = %q, want: %q", got, want)
+	}
+
+	if got := indent("\t", "}\n}"); got != "}\n\t}" {
+		t.Errorf("indent got = %q, want: %q", got, "}\n\t}")
+	}
+}
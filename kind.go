@@ -0,0 +1,191 @@
+package gounit
+
+//Kind is a kind of the generated functions, see Options.Kind
+type Kind string
+
+const (
+	//KindTest is a kind of the tests: TestXxx(t *testing.T)
+	KindTest Kind = "test"
+
+	//KindBenchmark is a kind of the benchmarks: BenchmarkXxx(b *testing.B)
+	KindBenchmark Kind = "bench"
+
+	//KindFuzz is a kind of the fuzz tests: FuzzXxx(f *testing.F), they are
+	//generated only for the functions with fuzzable params (see Func.IsFuzzable)
+	KindFuzz Kind = "fuzz"
+
+	//KindExample is a kind of the examples: ExampleXxx(), they are
+	//generated only for the exported functions (see Func.IsExported)
+	KindExample Kind = "example"
+)
+
+//FuncName returns a name of the generated function of this kind for f
+func (k Kind) FuncName(f *Func) string {
+	switch k {
+	case KindBenchmark:
+		return f.BenchmarkName()
+	case KindFuzz:
+		return f.FuzzName()
+	case KindExample:
+		return f.ExampleName()
+	}
+
+	return f.TestName()
+}
+
+//builtinTemplates are used when the test template doesn't define
+//a template of the kind, i.e. {{define "bench"}}...{{end}}
+var builtinTemplates = map[Kind]string{
+	KindBenchmark: benchmarkTemplate,
+	KindFuzz:      fuzzTemplate,
+	KindExample:   exampleTemplate,
+}
+
+var benchmarkTemplate = `{{$func := .Func}}
+
+func {{ $func.BenchmarkName }}(b *testing.B) {
+	{{- if $func.IsGeneric }}
+		//TODO: replace placeholder type arguments
+		type (
+			{{- range $alias := typeAliases $func }}
+				{{ $alias }}
+			{{- end }}
+		)
+	{{ end -}}
+	{{- if (gt $func.NumParams 0) }}
+		type args struct {
+			{{ range $param := params $func }}
+				{{- $param}}
+			{{ end }}
+		}
+	{{ end -}}
+	benchmarks := []struct {
+		name string
+		{{- if $func.IsMethod }}
+			init func(t testing.TB) {{ ast $func.ReceiverType }}
+		{{- end }}
+		{{- if (gt $func.NumParams 0) }}
+			args func(t testing.TB) args
+		{{- end }}
+	}{
+		{{- if eq .Comment "" }}
+			//TODO: Add benchmark cases
+		{{else}}
+			//{{ .Comment }}
+		{{end -}}
+		{
+			name: "zero values",
+			{{- if $func.IsMethod }}
+				init: func(t testing.TB) {{ ast $func.ReceiverType }} {
+					{{ construct $func }}
+				},
+			{{- end }}
+			{{- if (gt $func.NumParams 0) }}
+				args: func(t testing.TB) args {
+					return args{
+						{{- range $param := params $func }}
+							{{ fieldName $param }}: {{ zero $func $param }},
+						{{- end }}
+					}
+				},
+			{{- end }}
+		},
+	}
+
+	for _, bb := range benchmarks {
+		b.Run(bb.name, func(b *testing.B) {
+			{{- if (gt $func.NumParams 0) }}
+				tArgs := bb.args(b)
+			{{- end }}
+			{{- if $func.IsMethod }}
+				receiver := bb.init(b)
+			{{- end }}
+			{{- if (gt $func.NumResults 0) }}
+				var (
+					{{- range $result := results $func }}
+						{{ $result }}
+					{{- end }}
+					{{- if $func.ReturnsError }}
+						err error
+					{{- end }}
+				)
+			{{- end }}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				{{ if (gt $func.NumResults 0) }}{{ join $func.ResultsNames ", " }} = {{ end -}}
+				{{ if $func.IsMethod }}receiver.{{ $func.Name }}{{ else }}{{ $func.QualifiedName }}{{ $func.TypeParamsList }}{{ end }}(
+					{{- range $i, $pn := $func.ParamsNames }}
+						{{- if not (eq $i 0) }}, {{ end }}tArgs.{{ $pn }}{{ end }})
+			}
+			{{- if (gt $func.NumResults 0) }}
+
+				sink{{ $func.BenchmarkName }} = []interface{}{ {{- join $func.ResultsNames ", " -}} }
+			{{- end }}
+		})
+	}
+}
+{{- if (gt $func.NumResults 0) }}
+
+//sink{{ $func.BenchmarkName }} keeps the results of the benchmarked calls so they aren't optimized away
+var sink{{ $func.BenchmarkName }} interface{}
+{{- end }}`
+
+var fuzzTemplate = `{{$func := .Func}}
+
+func {{ $func.FuzzName }}(f *testing.F) {
+	{{- if $func.IsGeneric }}
+		//TODO: replace placeholder type arguments
+		type (
+			{{- range $alias := typeAliases $func }}
+				{{ $alias }}
+			{{- end }}
+		)
+	{{ end -}}
+	{{- if $func.IsMethod }}
+		newReceiver := func(t testing.TB) {{ ast $func.ReceiverType }} {
+			{{ construct $func }}
+		}
+	{{ end }}
+	{{- if eq .Comment "" }}
+		//TODO: Add seed corpus entries
+	{{else}}
+		//{{ .Comment }}
+	{{end -}}
+	f.Add({{ join (seeds $func) ", " }})
+
+	f.Fuzz(func(t *testing.T, {{ join (fuzzParams $func) ", " }}) {
+		{{- if $func.IsMethod }}
+			receiver := newReceiver(t)
+		{{- end }}
+		{{ if $func.IsMethod }}receiver.{{ $func.Name }}{{ else }}{{ $func.QualifiedName }}{{ $func.TypeParamsList }}{{ end }}({{ join $func.FuzzArgs ", " }})
+	})
+}`
+
+var exampleTemplate = `{{$func := .Func}}
+
+func {{ $func.ExampleName }}() {
+	{{- if $func.IsGeneric }}
+		//TODO: replace placeholder type arguments
+		type (
+			{{- range $alias := typeAliases $func }}
+				{{ $alias }}
+			{{- end }}
+		)
+	{{ end -}}
+	{{- if $func.IsMethod }}
+		receiver := {{ zero $func $func.ReceiverType }}
+	{{ end }}
+	{{- if eq .Comment "" }}
+		//TODO: Replace zero values with meaningful arguments and add the expected output
+	{{else}}
+		//{{ .Comment }}
+	{{end -}}
+	{{ if (gt $func.NumResults 0) }}{{ join $func.ResultsNames ", " }} := {{ end -}}
+	{{ if $func.IsMethod }}receiver.{{ $func.Name }}{{ else }}{{ $func.QualifiedName }}{{ $func.TypeParamsList }}{{ end }}({{ join (zeroArgs $func) ", " }})
+	{{- if (gt $func.NumResults 0) }}
+		fmt.Println({{ join $func.ResultsNames ", " }})
+	{{- end }}
+
+	//the example isn't run until the expected output is uncommented
+	//// Output:
+}`
@@ -0,0 +1,175 @@
+package gounit
+
+import (
+	"bytes"
+	"go/ast"
+	"strings"
+	"testing"
+)
+
+func TestKind_FuncName(t *testing.T) {
+	method := &Func{Signature: &ast.FuncDecl{
+		Name: &ast.Ident{Name: "Method"},
+		Recv: &ast.FieldList{List: []*ast.Field{{Type: &ast.StarExpr{X: &ast.Ident{Name: "T"}}}}},
+	}}
+
+	tests := []struct {
+		name string
+		k    Kind
+		f    *Func
+
+		want1 string
+	}{
+		{
+			name:  "default",
+			f:     method,
+			want1: "TestT_Method",
+		},
+		{
+			name:  "test",
+			k:     KindTest,
+			f:     &Func{Signature: &ast.FuncDecl{Name: &ast.Ident{Name: "function"}}},
+			want1: "Test_function",
+		},
+		{
+			name:  "benchmark",
+			k:     KindBenchmark,
+			f:     method,
+			want1: "BenchmarkT_Method",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := tt.k.FuncName(tt.f)
+
+			if got1 != tt.want1 {
+				t.Errorf("Kind.FuncName got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestNewGenerator_benchmark(t *testing.T) {
+	const (
+		src = `package bench
+
+func Sum(a, b int) int { return a + b }
+
+func Add(int, int) int { return 0 }
+
+func Print(s fmt.Stringer) {}
+
+func Max(a, b int) int { return a }
+
+func min(a, b int) int { return a }
+`
+		testSrc = `package bench
+
+import "testing"
+
+func BenchmarkMax(b *testing.B) {}
+
+func ExamplePrint() {}
+`
+	)
+
+	tests := []struct {
+		name     string
+		template string
+		kind     Kind
+
+		wantTests string
+		wantCode  []string
+		wantErr   bool
+	}{
+		{
+			name:      "built-in template",
+			template:  "{{ .Func.TestName }}",
+			kind:      KindBenchmark,
+			wantTests: "TestSum,TestAdd,TestPrint,Test_min",
+			wantCode: []string{
+				"func BenchmarkSum(b *testing.B) {",
+				"tArgs := bb.args(b)",
+				"got1 = Sum(tArgs.a, tArgs.b)",
+				"sinkBenchmarkSum = []interface{}{got1}",
+				"var sinkBenchmarkSum interface{}",
+			},
+		},
+		{
+			name: "template defined in the test template",
+			template: `{{ define "bench" }}
+func {{ .Func.BenchmarkName }}(b *testing.B) {}
+{{ end }}`,
+			kind:      KindBenchmark,
+			wantTests: "TestSum,TestAdd,TestPrint,Test_min",
+			wantCode:  []string{"func BenchmarkSum(b *testing.B) {}"},
+		},
+		{
+			name:      "fuzz test",
+			template:  "{{ .Func.TestName }}",
+			kind:      KindFuzz,
+			wantTests: "TestSum,TestAdd,TestMax,Test_min",
+			wantCode: []string{
+				"func FuzzSum(f *testing.F) {",
+				"f.Add(0, 0)",
+				"f.Fuzz(func(t *testing.T, a int, b int) {",
+			},
+		},
+		{
+			name:      "example",
+			template:  "{{ .Func.TestName }}",
+			kind:      KindExample,
+			wantTests: "TestSum,TestAdd,TestMax",
+			wantCode: []string{
+				"func ExampleSum() {",
+				"got1 := Sum(0, 0)",
+				"got1 := Add(0, 0)",
+				"fmt.Println(got1)",
+				"\t//// Output:\n}",
+			},
+		},
+		{
+			name:     "unknown kind",
+			template: "{{ .Func.TestName }}",
+			kind:     "unknown",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := Options{
+				All:        true,
+				InputFile:  "bench.go",
+				OutputFile: "bench_test.go",
+				Template:   tt.template,
+				Kind:       tt.kind,
+			}
+
+			g, err := NewGenerator(opt, strings.NewReader(src), strings.NewReader(testSrc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGenerator error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := testNames(g.Funcs()); got != tt.wantTests {
+				t.Fatalf("Generator.Funcs = %v, want: %v", got, tt.wantTests)
+			}
+
+			buf := bytes.NewBuffer([]byte{})
+			if err := g.Write(buf); err != nil {
+				t.Fatalf("Generator.Write: %v", err)
+			}
+
+			for _, want := range tt.wantCode {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("generated code doesn't contain %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package gounit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidFrontMatter = GenericError("invalid template front matter: %v")
+)
+
+//frontMatterDelimiter starts and ends the front matter of the template
+const frontMatterDelimiter = "---"
+
+//TemplateMeta is a metadata of the template declared in its front matter:
+//
+//	---
+//	name: testify
+//	description: table-driven tests with testify assertions
+//	author: John Doe
+//	version: 1.0.0
+//	go: 1.18
+//	imports:
+//	  - github.com/stretchr/testify/assert
+//	---
+//	{{$func := .Func}}
+//	...
+type TemplateMeta struct {
+	Name        string
+	Description string
+	Author      string
+	Version     string
+
+	//GoVersion is a version of Go the generated code targets
+	GoVersion string
+
+	//Imports are import paths added to every generated test file
+	Imports []string
+}
+
+//ParseTemplate splits the contents of the template file into the metadata declared
+//in the front matter and the template itself. Templates without front matter have
+//empty metadata
+func ParseTemplate(contents string) (TemplateMeta, string, error) {
+	var meta TemplateMeta
+
+	lines := strings.SplitAfter(contents, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		return meta, contents, nil
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontMatterDelimiter {
+			end = i
+			break
+		}
+	}
+
+	if end < 0 {
+		return meta, "", ErrInvalidFrontMatter.Format("closing " + frontMatterDelimiter + " not found")
+	}
+
+	fields, err := parseMetadata(strings.Join(lines[1:end], ""))
+	if err != nil {
+		return meta, "", ErrInvalidFrontMatter.Format(err)
+	}
+
+	for key, value := range fields {
+		var s string
+		if v, ok := value.(string); ok {
+			s = v
+		} else if key != "imports" {
+			return meta, "", ErrInvalidFrontMatter.Format(fmt.Sprintf("%s: string expected", key))
+		}
+
+		switch key {
+		case "name":
+			meta.Name = s
+		case "description":
+			meta.Description = s
+		case "author":
+			meta.Author = s
+		case "version":
+			meta.Version = s
+		case "go":
+			meta.GoVersion = s
+		case "imports":
+			meta.Imports = stringList(value)
+		default:
+			return meta, "", ErrInvalidFrontMatter.Format(fmt.Sprintf("unknown field %q", key))
+		}
+	}
+
+	return meta, strings.Join(lines[end+1:], ""), nil
+}
+
+//parseMetadata parses a subset of YAML used in the front matter of the templates:
+//"key: value" pairs and lists of the values, either inline "key: [a, b]" or one
+//"- value" per line after the "key:" line. Empty lines and comments are ignored.
+//Values are either strings or slices of strings
+func parseMetadata(data string) (map[string]interface{}, error) {
+	var (
+		fields = map[string]interface{}{}
+		list   string //key of the list being parsed
+	)
+
+	for n, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if list == "" {
+				return nil, fmt.Errorf("line %d: list item without a key", n+1)
+			}
+
+			value, err := unquote(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n+1, err)
+			}
+
+			fields[list] = append(fields[list].([]string), value)
+			continue
+		}
+
+		i := strings.Index(trimmed, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: key: value pair expected", n+1)
+		}
+
+		key, value := strings.TrimSpace(trimmed[:i]), strings.TrimSpace(trimmed[i+1:])
+		if _, ok := fields[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %q", n+1, key)
+		}
+
+		list = ""
+
+		switch {
+		case value == "":
+			list = key
+			fields[key] = []string{}
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			items := []string{}
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = strings.TrimSpace(item); item == "" {
+					continue
+				}
+
+				item, err := unquote(item)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %v", n+1, err)
+				}
+				items = append(items, item)
+			}
+			fields[key] = items
+		default:
+			s, err := unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n+1, err)
+			}
+			fields[key] = s
+		}
+	}
+
+	return fields, nil
+}
+
+//unquote removes quotes from the quoted value
+func unquote(value string) (string, error) {
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		return strconv.Unquote(value)
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1), nil
+	}
+
+	return value, nil
+}
+
+//stringList converts a value returned by parseMetadata to a slice of strings
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	}
+
+	return nil
+}
+
+//String implements fmt.Stringer
+func (m TemplateMeta) String() string {
+	fields := [][2]string{
+		{"name", m.Name},
+		{"description", m.Description},
+		{"author", m.Author},
+		{"version", m.Version},
+		{"go", m.GoVersion},
+		{"imports", strings.Join(m.Imports, ", ")},
+	}
+
+	var lines []string
+	for _, field := range fields {
+		if field[1] != "" {
+			lines = append(lines, field[0]+": "+field[1])
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,161 @@
+package gounit
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+
+		want1   TemplateMeta
+		want2   string
+		wantErr bool
+	}{
+		{
+			name:     "no front matter",
+			contents: "{{ .Func.TestName }}\n",
+			want2:    "{{ .Func.TestName }}\n",
+		},
+		{
+			name: "front matter",
+			contents: `---
+# comments are ignored
+name: testify
+description: "table-driven tests: testify"
+author: 'John Doe'
+version: 1.0.0
+go: 1.18
+imports:
+  - github.com/stretchr/testify/assert
+  - "github.com/stretchr/testify/require"
+---
+{{ .Func.TestName }}
+`,
+			want1: TemplateMeta{
+				Name:        "testify",
+				Description: "table-driven tests: testify",
+				Author:      "John Doe",
+				Version:     "1.0.0",
+				GoVersion:   "1.18",
+				Imports:     []string{"github.com/stretchr/testify/assert", "github.com/stretchr/testify/require"},
+			},
+			want2: "{{ .Func.TestName }}\n",
+		},
+		{
+			name:     "inline list of imports",
+			contents: "---\nimports: [io, \"os\"]\n---\n",
+			want1:    TemplateMeta{Imports: []string{"io", "os"}},
+		},
+		{
+			name:     "front matter is not closed",
+			contents: "---\nname: test\n{{ .Func.TestName }}\n",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown field",
+			contents: "---\nlicense: MIT\n---\n",
+			wantErr:  true,
+		},
+		{
+			name:     "list instead of string",
+			contents: "---\nname: [a, b]\n---\n",
+			wantErr:  true,
+		},
+		{
+			name:     "list item without a key",
+			contents: "---\n- io\n---\n",
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate key",
+			contents: "---\nname: a\nname: b\n---\n",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid line",
+			contents: "---\nname\n---\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1, got2, err := ParseTemplate(tt.contents)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTemplate error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("ParseTemplate got1 = %#v, want1: %#v", got1, tt.want1)
+			}
+
+			if got2 != tt.want2 {
+				t.Errorf("ParseTemplate got2 = %q, want2: %q", got2, tt.want2)
+			}
+		})
+	}
+}
+
+func TestTemplateMeta_String(t *testing.T) {
+	meta := TemplateMeta{Name: "testify", Version: "1.0.0", Imports: []string{"io", "os"}}
+
+	if got, want := meta.String(), "name: testify\nversion: 1.0.0\nimports: io, os"; got != want {
+		t.Errorf("TemplateMeta.String got = %q, want: %q", got, want)
+	}
+}
+
+func TestNewGenerator_requiredImports(t *testing.T) {
+	const (
+		src = `package meta
+
+import "io"
+
+func Copy(w io.Writer) {}
+`
+		tmpl = `---
+imports:
+  - io
+  - github.com/stretchr/testify/assert
+---
+{{ $func := .Func }}
+func {{ $func.TestName }}(t *testing.T) {
+	assert.NotNil(t, io.EOF)
+}
+`
+	)
+
+	opt := Options{All: true, InputFile: "meta.go", OutputFile: "meta_test.go", Template: tmpl}
+
+	g, err := NewGenerator(opt, strings.NewReader(src), nil)
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := g.WriteHeader(buf); err != nil {
+		t.Fatalf("Generator.WriteHeader: %v", err)
+	}
+
+	if got := strings.Count(buf.String(), `"io"`); got != 1 {
+		t.Errorf("io package is imported %d times:\n%s", got, buf.String())
+	}
+
+	if !strings.Contains(buf.String(), `"github.com/stretchr/testify/assert"`) {
+		t.Errorf("required import is missing:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := g.WriteTests(buf); err != nil {
+		t.Fatalf("Generator.WriteTests: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "---") {
+		t.Errorf("front matter is rendered:\n%s", buf.String())
+	}
+}
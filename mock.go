@@ -0,0 +1,574 @@
+package gounit
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/imports"
+)
+
+var (
+	ErrInterfaceNotFound = GenericError("unable to find an interface declaration")
+	ErrGenerateMock      = GenericError("failed to write mock: %v")
+	ErrStaleMock         = GenericError("%s declared in %s doesn't match the interface, remove it to regenerate")
+)
+
+//MockOptions are options of the MockGenerator
+type MockOptions struct {
+	//Input is either a .go file or a package directory
+	Input string
+
+	//OutputFile is a name of the _test.go file to write mocks to
+	OutputFile string
+
+	//Interfaces is a list of names of the interfaces to mock,
+	//all interfaces are mocked if it's empty
+	Interfaces []string
+}
+
+//MockGenerator generates hand-rolled fakes of the interfaces
+type MockGenerator struct {
+	opt     MockOptions
+	fs      *token.FileSet
+	pkg     *types.Package
+	dstPkg  string
+	srcPath string
+	ifaces  []*types.TypeName
+	src     []byte
+	imports map[string]string
+
+	//stale are the mocks declared in the output file that don't match
+	//their interfaces anymore, they're removed before the new ones are written
+	stale []*mockDecls
+}
+
+//NewMockGenerator returns a pointer to MockGenerator
+func NewMockGenerator(opt MockOptions) (*MockGenerator, error) {
+	fi, err := os.Stat(opt.Input)
+	if err != nil {
+		return nil, ErrFailedToOpenInFile.Format(err)
+	}
+
+	var (
+		fs    = token.NewFileSet()
+		dir   = opt.Input
+		files []*ast.File
+	)
+
+	if fi.IsDir() {
+		files = parsePackageFiles(fs, "", dir, "")
+	} else {
+		dir = filepath.Dir(opt.Input)
+
+		file, err := parser.ParseFile(fs, opt.Input, nil, 0)
+		if err != nil {
+			return nil, ErrFailedToParseInFile.Format(err)
+		}
+
+		files = append([]*ast.File{file}, parsePackageFiles(fs, file.Name.Name, dir, filepath.Base(opt.Input))...)
+	}
+
+	if len(files) == 0 {
+		return nil, ErrInterfaceNotFound
+	}
+
+	pkg, _ := typeCheck(fs, files)
+
+	g := &MockGenerator{
+		opt:     opt,
+		fs:      fs,
+		pkg:     pkg,
+		dstPkg:  pkg.Name(),
+		imports: map[string]string{},
+	}
+
+	if g.src, err = ioutil.ReadFile(opt.OutputFile); err != nil && !os.IsNotExist(err) {
+		return nil, ErrFailedToOpenOutFile.Format(err)
+	}
+
+	if len(g.src) > 0 {
+		file, err := parser.ParseFile(fs, opt.OutputFile, g.src, parser.PackageClauseOnly)
+		if err != nil {
+			return nil, ErrFailedToParseOutFile.Format(err)
+		}
+
+		g.dstPkg = file.Name.Name
+	}
+
+	//mocks written to the external test package have to import the source package
+	if g.dstPkg != pkg.Name() {
+		if g.srcPath, err = importPath(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	declared := findMocks(fs, filepath.Dir(opt.OutputFile))
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+
+		//type constraints and empty interfaces can't be mocked
+		iface, isInterface := obj.Type().Underlying().(*types.Interface)
+		if !isInterface || !iface.IsMethodSet() || iface.NumMethods() == 0 {
+			continue
+		}
+
+		if !fi.IsDir() && fs.Position(obj.Pos()).Filename != opt.Input {
+			continue
+		}
+
+		if len(opt.Interfaces) > 0 && !contains(opt.Interfaces, name) {
+			continue
+		}
+
+		//existing mocks are regenerated only if they're stale and they are declared
+		//in the output file, mocks declared in the other files are left to the user
+		if existing := declared[name+"Mock"]; existing != nil {
+			sameFile := sameFile(existing.file, opt.OutputFile)
+			if existing.matches(g.methodSignatures(obj), sameFile) {
+				continue
+			}
+
+			if !sameFile {
+				return nil, ErrStaleMock.Format(name+"Mock", existing.file)
+			}
+
+			g.stale = append(g.stale, existing)
+		}
+
+		g.ifaces = append(g.ifaces, obj)
+	}
+
+	if len(g.ifaces) == 0 {
+		return nil, ErrInterfaceNotFound
+	}
+
+	return g, nil
+}
+
+//Interfaces returns names of the interfaces that will be mocked
+func (g *MockGenerator) Interfaces() []string {
+	var names []string
+	for _, iface := range g.ifaces {
+		names = append(names, iface.Name())
+	}
+
+	return names
+}
+
+//Write writes the contents of the output file with the generated mocks to w
+func (g *MockGenerator) Write(w io.Writer) error {
+	buf := bytes.NewBuffer([]byte{})
+
+	if len(g.src) > 0 {
+		buf.Write(g.removeStale(g.src))
+	} else {
+		fmt.Fprintf(buf, "package %s\n", g.dstPkg)
+	}
+
+	g.imports["sync"] = "sync"
+
+	for _, iface := range g.ifaces {
+		g.writeMock(buf, iface)
+	}
+
+	file, err := parser.ParseFile(g.fs, g.opt.OutputFile, buf.Bytes(), parser.ParseComments)
+	if err != nil {
+		return ErrGenerateMock.Format(err)
+	}
+
+	for importPath, name := range g.imports {
+		if name == path.Base(importPath) {
+			name = ""
+		}
+		astutil.AddNamedImport(g.fs, file, name, importPath)
+	}
+
+	buf.Reset()
+	if err := format.Node(buf, g.fs, file); err != nil {
+		return ErrGenerateMock.Format(err)
+	}
+
+	formattedSource, err := imports.Process(g.opt.OutputFile, buf.Bytes(), nil)
+	if err != nil {
+		return ErrFixImports.Format(err)
+	}
+
+	if _, err = w.Write(formattedSource); err != nil {
+		return ErrWriteTest.Format(err)
+	}
+
+	return nil
+}
+
+//mockMethod is a method of the mocked interface
+type mockMethod struct {
+	name     string
+	params   []mockParam
+	results  string
+	variadic bool
+}
+
+//mockParam is a param of the mocked method
+type mockParam struct {
+	name  string
+	field string
+	typ   string
+}
+
+//writeMock writes a fake implementation of the interface to w
+func (g *MockGenerator) writeMock(w io.Writer, obj *types.TypeName) {
+	var (
+		name       = obj.Name()
+		mock       = name + "Mock"
+		typeParams string
+		typeArgs   string
+		iface      = obj.Type().Underlying().(*types.Interface)
+		methods    []mockMethod
+	)
+
+	if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		var params, args []string
+		for i := 0; i < named.TypeParams().Len(); i++ {
+			tp := named.TypeParams().At(i)
+			params = append(params, tp.Obj().Name()+" "+types.TypeString(tp.Constraint(), g.qualifier))
+			args = append(args, tp.Obj().Name())
+		}
+		typeParams = "[" + strings.Join(params, ", ") + "]"
+		typeArgs = "[" + strings.Join(args, ", ") + "]"
+	}
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		methods = append(methods, g.method(iface.Method(i)))
+	}
+
+	fmt.Fprintf(w, "\n//%s is a fake implementation of the %s interface\n", mock, name)
+	fmt.Fprintf(w, "type %s%s struct {\nmu sync.Mutex\n", mock, typeParams)
+	for _, m := range methods {
+		fmt.Fprintf(w, "\n//%sFunc is called by %s method\n%sFunc func(%s) %s\n", m.name, m.name, m.name, m.signature(), m.results)
+		fmt.Fprintf(w, "//%sCalls holds params of all %s calls\n%sCalls []%s%sParams%s\n", m.name, m.name, m.name, mock, m.name, typeArgs)
+	}
+	fmt.Fprintf(w, "}\n")
+
+	for _, m := range methods {
+		var fields, values, args []string
+		for _, p := range m.params {
+			fields = append(fields, p.field+" "+strings.Replace(p.typ, "...", "[]", 1))
+			values = append(values, p.field+": "+p.name)
+			args = append(args, p.name)
+		}
+
+		if m.variadic {
+			args[len(args)-1] += "..."
+		}
+
+		call := fmt.Sprintf("m.%sFunc(%s)", m.name, strings.Join(args, ", "))
+		if m.results != "" {
+			call = "return " + call
+		}
+
+		fmt.Fprintf(w, "\n//%s%sParams holds params of the %s.%s call\n", mock, m.name, mock, m.name)
+		fmt.Fprintf(w, "type %s%sParams%s struct {\n%s\n}\n", mock, m.name, typeParams, strings.Join(fields, "\n"))
+
+		fmt.Fprintf(w, "\n//%s implements %s\n", m.name, name)
+		fmt.Fprintf(w, "func (m *%s%s) %s(%s) %s {\n", mock, typeArgs, m.name, m.signature(), m.results)
+		fmt.Fprintf(w, "m.mu.Lock()\nm.%sCalls = append(m.%sCalls, %s%sParams%s{%s})\nm.mu.Unlock()\n\n", m.name, m.name, mock, m.name, typeArgs, strings.Join(values, ", "))
+		fmt.Fprintf(w, "if m.%sFunc == nil {\npanic(\"%s.%s: unexpected call\")\n}\n\n%s\n}\n", m.name, mock, m.name, call)
+	}
+}
+
+//method returns a description of the interface method
+func (g *MockGenerator) method(f *types.Func) mockMethod {
+	var (
+		sig    = f.Type().(*types.Signature)
+		m      = mockMethod{name: f.Name(), variadic: sig.Variadic()}
+		used   = map[string]bool{"m": true}
+		fields = map[string]bool{}
+	)
+
+	for i := 0; i < sig.Params().Len(); i++ {
+		v := sig.Params().At(i)
+
+		name := v.Name()
+		if name == "" || name == "_" || used[name] {
+			name = fmt.Sprintf("p%d", i+1)
+		}
+		used[name] = true
+
+		field := exportedName(name)
+		if fields[field] {
+			field = fmt.Sprintf("P%d", i+1)
+		}
+		fields[field] = true
+
+		typ := types.TypeString(v.Type(), g.qualifier)
+		if m.variadic && i == sig.Params().Len()-1 {
+			typ = "..." + types.TypeString(v.Type().(*types.Slice).Elem(), g.qualifier)
+		}
+
+		m.params = append(m.params, mockParam{name: name, field: field, typ: typ})
+	}
+
+	var results []string
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, types.TypeString(sig.Results().At(i).Type(), g.qualifier))
+	}
+
+	switch len(results) {
+	case 0:
+	case 1:
+		m.results = results[0]
+	default:
+		m.results = "(" + strings.Join(results, ", ") + ")"
+	}
+
+	return m
+}
+
+//signature returns a list of method params with their types
+func (m mockMethod) signature() string {
+	var params []string
+	for _, p := range m.params {
+		params = append(params, p.name+" "+p.typ)
+	}
+
+	return strings.Join(params, ", ")
+}
+
+//qualifier qualifies the types from the packages other than the destination one
+//and registers these packages as imports of the output file
+func (g *MockGenerator) qualifier(p *types.Package) string {
+	if p != g.pkg {
+		g.imports[p.Path()] = p.Name()
+		return p.Name()
+	}
+
+	if g.dstPkg == g.pkg.Name() {
+		return ""
+	}
+
+	g.imports[g.srcPath] = p.Name()
+	return p.Name()
+}
+
+//mockDecls are declarations of the existing mock: the mock type, the types
+//of the call params and the methods
+type mockDecls struct {
+	file    string
+	decls   []ast.Decl
+	methods map[string]string
+}
+
+//matches returns true if the mock implements the methods with the given signatures,
+//if exact is true the mock must not have any other methods
+func (m *mockDecls) matches(signatures map[string]string, exact bool) bool {
+	for name, signature := range signatures {
+		if m.methods[name] != signature {
+			return false
+		}
+	}
+
+	return !exact || len(m.methods) == len(signatures)
+}
+
+//findMocks returns declarations of the types that look like mocks, i.e. "StoreMock",
+//by the type name. Types are looked up in the _test.go files located in dir
+func findMocks(fs *token.FileSet, dir string) map[string]*mockDecls {
+	filter := func(fi os.FileInfo) bool {
+		return strings.HasSuffix(fi.Name(), "_test.go")
+	}
+
+	mocks := map[string]*mockDecls{}
+
+	packages, err := parser.ParseDir(fs, dir, filter, parser.ParseComments)
+	if err != nil {
+		return mocks
+	}
+
+	for _, pkg := range packages {
+		for filename, file := range pkg.Files {
+			for name, decls := range collectMocks(fs, file) {
+				decls.file = filename
+				mocks[name] = decls
+			}
+		}
+	}
+
+	return mocks
+}
+
+//collectMocks returns declarations of the types declared in the file and
+//their methods, the types of the call params are collected for the types
+//named like mocks
+func collectMocks(fs *token.FileSet, file *ast.File) map[string]*mockDecls {
+	mocks := map[string]*mockDecls{}
+	for name := range findTypeSpecs(file.Decls) {
+		mocks[name] = &mockDecls{methods: map[string]string{}}
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE || len(d.Specs) != 1 {
+				continue
+			}
+
+			name := d.Specs[0].(*ast.TypeSpec).Name.Name
+			if m := mocks[name]; m != nil {
+				m.decls = append(m.decls, d)
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) != 1 {
+				continue
+			}
+
+			m := mocks[receiverName(d.Recv.List[0].Type)]
+			if m == nil {
+				continue
+			}
+
+			m.methods[d.Name.Name] = funcTypeString(fs, d.Type)
+			m.decls = append(m.decls, d)
+		}
+	}
+
+	//types of the call params, i.e. "StoreMockGetParams", belong to the mock
+	for name, m := range mocks {
+		for method := range m.methods {
+			if params := mocks[name+method+"Params"]; params != nil {
+				m.decls = append(m.decls, params.decls...)
+			}
+		}
+	}
+
+	return mocks
+}
+
+//receiverName returns a name of the receiver type, i.e. "T" for *T[K]
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	}
+
+	return ""
+}
+
+//funcTypeString returns types of the params and the results of the function
+//without the names of the params, i.e. "(string, ...int) error"
+func funcTypeString(fs *token.FileSet, ft *ast.FuncType) string {
+	fieldTypes := func(list *ast.FieldList) string {
+		var types []string
+		if list != nil {
+			for _, field := range list.List {
+				for n := numNames(field); n > 0; n-- {
+					types = append(types, nodeToString(fs, field.Type))
+				}
+			}
+		}
+
+		return strings.Join(types, ", ")
+	}
+
+	return "(" + fieldTypes(ft.Params) + ") (" + fieldTypes(ft.Results) + ")"
+}
+
+//methodSignatures returns signatures of the methods of the mock of the interface
+//as they're written by writeMock (see funcTypeString)
+func (g *MockGenerator) methodSignatures(obj *types.TypeName) map[string]string {
+	buf := bytes.NewBufferString("package mock\n")
+	g.writeMock(buf, obj)
+
+	fs := token.NewFileSet()
+	file, err := parser.ParseFile(fs, "", buf.Bytes(), 0)
+	if err != nil {
+		return nil
+	}
+
+	if m := collectMocks(fs, file)[obj.Name()+"Mock"]; m != nil {
+		return m.methods
+	}
+
+	return nil
+}
+
+//removeStale removes declarations of the stale mocks from the source of the output file
+func (g *MockGenerator) removeStale(src []byte) []byte {
+	var ranges [][2]int
+	for _, m := range g.stale {
+		for _, decl := range m.decls {
+			start := decl.Pos()
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Doc != nil {
+					start = d.Doc.Pos()
+				}
+			case *ast.FuncDecl:
+				if d.Doc != nil {
+					start = d.Doc.Pos()
+				}
+			}
+
+			ranges = append(ranges, [2]int{g.fs.Position(start).Offset, g.fs.Position(decl.End()).Offset})
+		}
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] > ranges[j][0] })
+
+	result := append([]byte(nil), src...)
+	for _, r := range ranges {
+		result = append(result[:r[0]], result[r[1]:]...)
+	}
+
+	return result
+}
+
+//sameFile returns true if both paths point to the same file
+func sameFile(path1, path2 string) bool {
+	abs1, err1 := filepath.Abs(path1)
+	abs2, err2 := filepath.Abs(path2)
+
+	return err1 == nil && err2 == nil && abs1 == abs2
+}
+
+//exportedName returns the name with the first letter in upper case
+func exportedName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+//contains returns true if the list contains s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,264 @@
+package gounit
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mockSource = `package store
+
+import "io"
+
+type Store interface {
+	io.Closer
+	Get(key string) ([]byte, error)
+	Set(_ string, m []byte, opts ...int)
+}
+
+type Getter[T any] interface {
+	Get() T
+}
+
+type Number interface {
+	~int | ~float64
+}
+
+type Empty interface{}
+`
+
+const declaredMocks = `package store
+
+type StoreMock struct{}
+
+func (m *StoreMock) Close() error                      { return nil }
+func (m *StoreMock) Get(key string) ([]byte, error)    { return nil, nil }
+func (m *StoreMock) Set(k string, v []byte, o ...int) {}
+
+type GetterMock[T any] struct{}
+
+func (m GetterMock[T]) Get() (t T) { return t }
+`
+
+func TestNewMockGenerator(t *testing.T) {
+	tests := []struct {
+		name       string
+		interfaces []string
+		testSource string
+
+		want1      []string
+		wantErr    bool
+		inspectErr func(err error, t *testing.T)
+	}{
+		{
+			name:  "all interfaces",
+			want1: []string{"Getter", "Store"},
+		},
+		{
+			name:       "selected interfaces",
+			interfaces: []string{"Store"},
+			want1:      []string{"Store"},
+		},
+		{
+			name:       "mocks already declared",
+			testSource: declaredMocks,
+			wantErr:    true,
+			inspectErr: func(err error, t *testing.T) {
+				if err != ErrInterfaceNotFound {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:       "stale mock declared in another file",
+			testSource: "package store\n\ntype StoreMock struct{}\n\nfunc (m *StoreMock) Close() error { return nil }\n",
+			wantErr:    true,
+			inspectErr: func(err error, t *testing.T) {
+				if err == nil || !strings.Contains(err.Error(), "StoreMock declared in") {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := mockDir(t, tt.testSource)
+			defer os.RemoveAll(dir)
+
+			g, err := NewMockGenerator(MockOptions{
+				Input:      filepath.Join(dir, "store.go"),
+				OutputFile: filepath.Join(dir, "store_mock_test.go"),
+				Interfaces: tt.interfaces,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewMockGenerator error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if tt.inspectErr != nil {
+				tt.inspectErr(err, t)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := strings.Join(g.Interfaces(), ","); got != strings.Join(tt.want1, ",") {
+				t.Errorf("NewMockGenerator interfaces = %v, want1: %v", got, tt.want1)
+			}
+		})
+	}
+}
+
+func TestMockGenerator_Write(t *testing.T) {
+	dir := mockDir(t, "")
+	defer os.RemoveAll(dir)
+
+	g, err := NewMockGenerator(MockOptions{
+		Input:      dir,
+		OutputFile: filepath.Join(dir, "mocks_test.go"),
+	})
+	if err != nil {
+		t.Fatalf("NewMockGenerator: %v", err)
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := g.Write(buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "", buf.Bytes(), 0); err != nil {
+		t.Fatalf("failed to parse generated mocks: %v", err)
+	}
+
+	for _, want := range []string{
+		"type StoreMock struct",
+		"CloseFunc func() error",
+		"func (m *StoreMock) Set(p1 string, p2 []byte, opts ...int)",
+		"m.SetFunc(p1, p2, opts...)",
+		"type GetterMock[T any] struct",
+		"func (m *GetterMock[T]) Get() T",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("generated mocks don't contain %q:\n%s", want, buf.String())
+		}
+	}
+
+	if strings.Contains(buf.String(), "NumberMock") || strings.Contains(buf.String(), "EmptyMock") {
+		t.Errorf("constraints and empty interfaces must not be mocked:\n%s", buf.String())
+	}
+}
+
+func TestMockGenerator_Write_stale(t *testing.T) {
+	dir := mockDir(t, "")
+	defer os.RemoveAll(dir)
+
+	opt := MockOptions{
+		Input:      filepath.Join(dir, "store.go"),
+		OutputFile: filepath.Join(dir, "mocks_test.go"),
+	}
+
+	generate := func(wantInterfaces string) {
+		t.Helper()
+
+		g, err := NewMockGenerator(opt)
+		if err != nil {
+			t.Fatalf("NewMockGenerator: %v", err)
+		}
+
+		if got := strings.Join(g.Interfaces(), ","); got != wantInterfaces {
+			t.Fatalf("NewMockGenerator interfaces = %v, want: %v", got, wantInterfaces)
+		}
+
+		buf := bytes.NewBuffer([]byte{})
+		if err := g.Write(buf); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+
+		if err := ioutil.WriteFile(opt.OutputFile, buf.Bytes(), 0600); err != nil {
+			t.Fatalf("failed to write mocks: %v", err)
+		}
+	}
+
+	generate("Getter,Store")
+
+	//mocks are up to date
+	if _, err := NewMockGenerator(opt); err != ErrInterfaceNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	source := strings.Replace(mockSource, "Get(key string) ([]byte, error)", "Get(key string) (string, error)\n\tDelete(key string)", 1)
+	if err := ioutil.WriteFile(opt.Input, []byte(source), 0600); err != nil {
+		t.Fatalf("failed to write source file: %v", err)
+	}
+
+	generate("Store")
+
+	mocks, err := ioutil.ReadFile(opt.OutputFile)
+	if err != nil {
+		t.Fatalf("failed to read mocks: %v", err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "", mocks, 0); err != nil {
+		t.Fatalf("failed to parse regenerated mocks: %v\n%s", err, mocks)
+	}
+
+	for want, count := range map[string]int{
+		"type StoreMock struct":                               1,
+		"type StoreMockGetParams struct":                      1,
+		"func (m *StoreMock) Get(key string) (string, error)": 1,
+		"func (m *StoreMock) Delete(key string)":              1,
+		"type GetterMock[T any] struct":                       1,
+	} {
+		if got := strings.Count(string(mocks), want); got != count {
+			t.Errorf("regenerated mocks contain %d of %q, want: %d\n%s", got, want, count, mocks)
+		}
+	}
+}
+
+func Test_exportedName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "lower case", in: "key", want: "Key"},
+		{name: "upper case", in: "Key", want: "Key"},
+		{name: "unicode", in: "ключ", want: "Ключ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exportedName(tt.in); got != tt.want {
+				t.Errorf("exportedName() = %q, want: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+//mockDir creates a temporary package directory with mockSource
+//and the optional test file
+func mockDir(t *testing.T, testSource string) string {
+	dir, err := ioutil.TempDir("", "gounit_mock")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "store.go"), []byte(mockSource), 0600); err != nil {
+		t.Fatalf("failed to write source file: %v", err)
+	}
+
+	if testSource != "" {
+		if err := ioutil.WriteFile(filepath.Join(dir, "store_test.go"), []byte(testSource), 0600); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+	}
+
+	return dir
+}
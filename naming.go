@@ -0,0 +1,174 @@
+package gounit
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidNaming = GenericError("invalid naming scheme %q: %v")
+)
+
+//DefaultNaming is a naming scheme of the tests used by default:
+//"TestT_Method" for the methods, "TestExported" and "Test_unexported" for the functions
+const DefaultNaming = "Test{{if .Type}}{{.Type}}_{{else if not .Exported}}_{{end}}{{.Name}}"
+
+//NamingData is passed to the naming scheme template
+type NamingData struct {
+	//Type is a name of the method receiver's base type, it's empty for the functions
+	Type string
+
+	//Name is a name of the tested function
+	Name string
+
+	//Exported is true if the tested function is exported
+	Exported bool
+}
+
+//NamingScheme names the tests of the functions, it's a template executed
+//with NamingData, i.e. "Test{{.Type}}_{{.Name}}" or "Test{{.Type}}{{.Name}}".
+//An underscore is inserted after the "Test" prefix if it's followed by a lower
+//case letter, i.e. "Test_unexported", otherwise go test doesn't run the test
+type NamingScheme struct {
+	tmpl *template.Template
+}
+
+//NewNamingScheme parses the naming scheme and checks that it produces valid
+//names of the tests for both methods and functions
+func NewNamingScheme(scheme string) (*NamingScheme, error) {
+	tmpl, err := template.New("naming").Option("missingkey=error").Parse(scheme)
+	if err != nil {
+		return nil, ErrInvalidNaming.Format(scheme, err)
+	}
+
+	n := &NamingScheme{tmpl: tmpl}
+
+	for _, data := range []NamingData{
+		{Type: "T", Name: "Method", Exported: true},
+		{Name: "Func", Exported: true},
+		{Name: "unexported"},
+	} {
+		name, err := n.execute(data)
+		if err != nil {
+			return nil, ErrInvalidNaming.Format(scheme, err)
+		}
+
+		if !isTestName(name) {
+			return nil, ErrInvalidNaming.Format(scheme, "test name expected, got "+name)
+		}
+	}
+
+	return n, nil
+}
+
+//TestName returns a name of the test of the function
+func (n *NamingScheme) TestName(f *Func) string {
+	name, _ := n.execute(NamingData{
+		Type:     f.ReceiverTypeName(),
+		Name:     f.Name(),
+		Exported: f.Signature.Name.IsExported(),
+	})
+
+	return name
+}
+
+func (n *NamingScheme) execute(data NamingData) (string, error) {
+	buf := bytes.NewBuffer([]byte{})
+	if err := n.tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+
+	name := buf.String()
+	if r, _ := utf8.DecodeRuneInString(strings.TrimPrefix(name, "Test")); strings.HasPrefix(name, "Test") && unicode.IsLower(r) {
+		name = "Test_" + name[len("Test"):]
+	}
+
+	return name, nil
+}
+
+//isTestName returns true if the name is a name of the function that is run by go test
+func isTestName(name string) bool {
+	if !token.IsIdentifier(name) || !strings.HasPrefix(name, "Test") {
+		return false
+	}
+
+	r, _ := utf8.DecodeRuneInString(name[len("Test"):])
+	return !unicode.IsLower(r)
+}
+
+//testNames returns the name of the test of the function followed by the names
+//of the tests in the common alternative styles, i.e. "TestTMethod" and
+//"TestT_method" for the method or "TestFunc" and "Test_Func" for the function.
+//Tests with any of these names are considered tests of the function. The names
+//with the capitalized name of the unexported function are skipped if there is
+//an exported function with the same name, i.e. "TestParse" is a test of "Parse"
+//rather than "parse"
+func (f *Func) testNames() []string {
+	name := f.Name()
+	title := strings.ToUpper(name[:1]) + name[1:]
+	twin := f.hasExportedTwin()
+
+	var names []string
+	if f.IsMethod() {
+		t := f.ReceiverTypeName()
+		names = []string{"Test" + t + "_" + name}
+		if !twin {
+			names = append(names, "Test"+t+"_"+title, "Test"+t+title)
+		}
+		names = append(names, "Test_"+t+"_"+name)
+	} else {
+		if !twin {
+			names = append(names, "Test"+title)
+		}
+		names = append(names, "Test_"+name)
+	}
+
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, n := range append([]string{f.TestName()}, names...) {
+		if !seen[n] {
+			seen[n] = true
+			unique = append(unique, n)
+		}
+	}
+
+	return unique
+}
+
+//hasExportedTwin returns true if the function is unexported and there is an exported
+//function (or a method of the same type) in the source package with the same name
+func (f *Func) hasExportedTwin() bool {
+	name := f.Name()
+	if f.Signature.Name.IsExported() {
+		return false
+	}
+
+	twin := strings.ToUpper(name[:1]) + name[1:]
+	if f.IsMethod() {
+		twin = f.ReceiverTypeName() + "." + twin
+	}
+
+	return f.declared[twin]
+}
+
+//declaredNames returns names of the functions and the methods (as "T.Method")
+//declared in the files
+func declaredNames(files []*ast.File) map[string]bool {
+	declared := map[string]bool{}
+	for _, file := range files {
+		for _, f := range findFunctions(file.Decls, func(*ast.FuncDecl) bool { return true }) {
+			if f.IsMethod() {
+				declared[f.ReceiverTypeName()+"."+f.Name()] = true
+			} else {
+				declared[f.Name()] = true
+			}
+		}
+	}
+
+	return declared
+}
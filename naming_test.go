@@ -0,0 +1,311 @@
+package gounit
+
+import (
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewNamingScheme(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme string
+
+		wantErr bool
+	}{
+		{name: "default", scheme: DefaultNaming},
+		{name: "underscore", scheme: "Test{{.Type}}_{{.Name}}"},
+		{name: "concatenation", scheme: "Test{{.Type}}{{.Name}}"},
+		{name: "invalid template", scheme: "Test{{.Name}", wantErr: true},
+		{name: "unknown field", scheme: "Test{{.Receiver}}", wantErr: true},
+		{name: "not a test", scheme: "Check{{.Name}}", wantErr: true},
+		{name: "not an identifier", scheme: "Test {{.Name}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNamingScheme(tt.scheme)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewNamingScheme error = %v, wantErr: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNamingScheme_TestName(t *testing.T) {
+	decls := []string{
+		"func (s *Service) Get() {}",
+		"func Exported() {}",
+		"func unexported() {}",
+	}
+
+	tests := []struct {
+		name   string
+		scheme string
+
+		want1 []string
+	}{
+		{
+			name:   "default",
+			scheme: DefaultNaming,
+			want1:  []string{"TestService_Get", "TestExported", "Test_unexported"},
+		},
+		{
+			name:   "underscore",
+			scheme: "Test{{.Type}}_{{.Name}}",
+			want1:  []string{"TestService_Get", "Test_Exported", "Test_unexported"},
+		},
+		{
+			name:   "concatenation",
+			scheme: "Test{{.Type}}{{.Name}}",
+			want1:  []string{"TestServiceGet", "TestExported", "Test_unexported"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			naming, err := NewNamingScheme(tt.scheme)
+			if err != nil {
+				t.Fatalf("NewNamingScheme: %v", err)
+			}
+
+			var got1 []string
+			for _, decl := range decls {
+				f := parseFunc(t, token.NewFileSet(), decl)
+				f.naming = naming
+				got1 = append(got1, f.TestName())
+			}
+
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("NamingScheme.TestName got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestFunc_testNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		decl     string
+		declared map[string]bool
+
+		want1 []string
+	}{
+		{
+			name:  "method",
+			decl:  "func (s *Service) get() {}",
+			want1: []string{"TestService_get", "TestService_Get", "TestServiceGet", "Test_Service_get"},
+		},
+		{
+			name:     "method with exported twin",
+			decl:     "func (s *Service) get() {}",
+			declared: map[string]bool{"Service.Get": true, "Service.get": true},
+			want1:    []string{"TestService_get", "Test_Service_get"},
+		},
+		{
+			name:     "method with exported twin of another type",
+			decl:     "func (s *Service) get() {}",
+			declared: map[string]bool{"Get": true, "Store.Get": true},
+			want1:    []string{"TestService_get", "TestService_Get", "TestServiceGet", "Test_Service_get"},
+		},
+		{
+			name:  "exported function",
+			decl:  "func Exported() {}",
+			want1: []string{"TestExported", "Test_Exported"},
+		},
+		{
+			name:  "unexported function",
+			decl:  "func unexported() {}",
+			want1: []string{"Test_unexported", "TestUnexported"},
+		},
+		{
+			name:     "unexported function with exported twin",
+			decl:     "func parse() {}",
+			declared: map[string]bool{"Parse": true, "parse": true},
+			want1:    []string{"Test_parse"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parseFunc(t, token.NewFileSet(), tt.decl)
+			f.declared = tt.declared
+
+			got1 := f.testNames()
+
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("Func.testNames got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestNewGenerator_naming(t *testing.T) {
+	const (
+		src = `package naming
+
+type Service struct{}
+
+func (s *Service) Get() int { return 0 }
+
+func (s *Service) Set(v int) {}
+
+func (s *Service) reset() {}
+
+func helper() {}
+`
+
+		testSrc = `package naming
+
+import "testing"
+
+func TestServiceGet(t *testing.T) {}
+
+func TestService_Set(t *testing.T) {}
+`
+	)
+
+	tests := []struct {
+		name   string
+		naming string
+
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default naming",
+			want: "TestService_reset,Test_helper",
+		},
+		{
+			name:   "custom naming",
+			naming: "Test{{.Type}}{{.Name}}",
+			want:   "TestServicereset,Test_helper",
+		},
+		{
+			name:    "invalid naming",
+			naming:  "{{.Name}}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := Options{
+				All:      true,
+				Naming:   tt.naming,
+				Template: "{{ $func := .Func }}\nfunc {{ $func.TestName }}(t *testing.T) {}\n",
+			}
+
+			g, err := NewGenerator(opt, strings.NewReader(src), strings.NewReader(testSrc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGenerator error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := testNames(g.Funcs()); got != tt.want {
+				t.Errorf("Generator.Funcs = %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGenerator_exportedTwins(t *testing.T) {
+	const (
+		src = `package naming
+
+type T struct{}
+
+func (t T) Get() {}
+
+func (t T) get() {}
+
+func Parse() {}
+
+func parse() {}
+
+func load() {}
+`
+
+		testSrc = `package naming
+
+import "testing"
+
+func TestT_Get(t *testing.T) {}
+
+func TestParse(t *testing.T) {}
+
+func TestLoad(t *testing.T) {}
+`
+	)
+
+	opt := Options{
+		All:      true,
+		Template: "{{ $func := .Func }}\nfunc {{ $func.TestName }}(t *testing.T) {}\n",
+	}
+
+	g, err := NewGenerator(opt, strings.NewReader(src), strings.NewReader(testSrc))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	if got, want := testNames(g.Funcs()), "TestT_get,Test_parse"; got != want {
+		t.Errorf("Generator.Funcs = %v, want: %v", got, want)
+	}
+}
+
+func TestCheck_naming(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gounit_check")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"naming.go": "package naming\n\ntype T struct{}\n\nfunc (t T) Method(a int) {}\n",
+		"naming_test.go": `package naming
+
+import "testing"
+
+func TestTMethod(t *testing.T) {
+	type args struct {
+		a int
+	}
+
+	tests := []struct {
+		name string
+		args args
+	}{}
+	_ = tests
+}
+`,
+	}
+
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	naming, err := NewNamingScheme("Test{{.Type}}{{.Name}}")
+	if err != nil {
+		t.Fatalf("NewNamingScheme: %v", err)
+	}
+
+	for _, naming := range []*NamingScheme{nil, naming} {
+		got1, err := Check(dir, naming)
+		if err != nil {
+			t.Fatalf("Check: %v", err)
+		}
+
+		if len(got1) != 0 {
+			t.Errorf("Check got1 = %v, want no problems", got1)
+		}
+	}
+}
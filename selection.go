@@ -0,0 +1,110 @@
+package gounit
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidPattern = GenericError("invalid function pattern %q: %v")
+)
+
+//nameMatcher reports whether the name matches the pattern
+type nameMatcher func(name string) bool
+
+//compilePattern compiles the pattern of the function names. Patterns enclosed
+//in slashes are regular expressions, i.e. "/^(Get|Set)/", other patterns
+//are globs, i.e. "Get*", "Service.*" or just a name of the function
+func compilePattern(pattern string) (nameMatcher, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, ErrInvalidPattern.Format(pattern, err)
+		}
+
+		return re.MatchString, nil
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, ErrInvalidPattern.Format(pattern, err)
+	}
+
+	return func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}, nil
+}
+
+//ValidatePattern returns an error if the pattern of the function names is malformed
+func ValidatePattern(pattern string) error {
+	_, err := compilePattern(pattern)
+	return err
+}
+
+//MatchPattern returns true if the pattern matches either the name of the function
+//or, for the methods, the name qualified with the receiver type, i.e. "T.Method"
+func (f *Func) MatchPattern(pattern string) (bool, error) {
+	match, err := compilePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return f.matches(match), nil
+}
+
+func (f *Func) matches(match nameMatcher) bool {
+	if match(f.Name()) {
+		return true
+	}
+
+	return f.IsMethod() && match(f.ReceiverTypeName()+"."+f.Name())
+}
+
+//newSelector returns a function that selects the functions by the Lines and
+//Functions options and filters them by visibility, kind and receiver type
+func newSelector(opt Options, line func(f *Func) int) (func(f *Func) bool, error) {
+	matchers := make([]nameMatcher, 0, len(opt.Functions))
+	for _, pattern := range opt.Functions {
+		match, err := compilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, match)
+	}
+
+	selected := func(f *Func) bool {
+		if opt.All {
+			return true
+		}
+
+		for _, l := range opt.Lines {
+			if l == line(f) {
+				return true
+			}
+		}
+
+		for _, match := range matchers {
+			if f.matches(match) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return func(f *Func) bool {
+		exported := f.Signature.Name.IsExported()
+
+		switch {
+		case opt.Exported && !exported, opt.Unexported && exported:
+			return false
+		case (opt.Methods || opt.ReceiverType != "") && !f.IsMethod(), opt.Funcs && f.IsMethod():
+			return false
+		case opt.ReceiverType != "" && f.ReceiverTypeName() != opt.ReceiverType:
+			return false
+		}
+
+		return selected(f)
+	}, nil
+}
@@ -0,0 +1,127 @@
+package gounit
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestFunc_MatchPattern(t *testing.T) {
+	const method = "func (s *Service) GetUser() {}"
+
+	tests := []struct {
+		name    string
+		decl    string
+		pattern string
+
+		want1   bool
+		wantErr bool
+	}{
+		{name: "name", decl: method, pattern: "GetUser", want1: true},
+		{name: "glob", decl: method, pattern: "Get*", want1: true},
+		{name: "qualified glob", decl: method, pattern: "Service.*", want1: true},
+		{name: "glob doesn't match", decl: method, pattern: "Set*", want1: false},
+		{name: "regexp", decl: method, pattern: "/^(Get|Set)/", want1: true},
+		{name: "qualified regexp", decl: method, pattern: "/^Service\\.Get/", want1: true},
+		{name: "regexp doesn't match", decl: "func getUser() {}", pattern: "/^Get/", want1: false},
+		{name: "type name isn't matched for functions", decl: "func GetUser() {}", pattern: "Service.*", want1: false},
+		{name: "invalid glob", decl: method, pattern: "[", wantErr: true},
+		{name: "invalid regexp", decl: method, pattern: "/(/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1, err := parseFunc(t, token.NewFileSet(), tt.decl).MatchPattern(tt.pattern)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Func.MatchPattern error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.MatchPattern got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestNewGenerator_selection(t *testing.T) {
+	const src = `package selection
+
+type Service struct{}
+
+func (s *Service) Get() int { return 0 }
+
+func (s *Service) reset() {}
+
+type Other struct{}
+
+func (o Other) Get() int { return 0 }
+
+func GetAll() []int { return nil }
+
+func helper() {}
+`
+
+	tests := []struct {
+		name string
+		opt  Options
+
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "all",
+			opt:  Options{All: true},
+			want: "TestService_Get,TestService_reset,TestOther_Get,TestGetAll,Test_helper",
+		},
+		{
+			name: "exported methods of the type",
+			opt:  Options{All: true, Exported: true, ReceiverType: "Service"},
+			want: "TestService_Get",
+		},
+		{
+			name: "unexported",
+			opt:  Options{All: true, Unexported: true},
+			want: "TestService_reset,Test_helper",
+		},
+		{
+			name: "methods matching the pattern",
+			opt:  Options{Functions: []string{"Get*"}, Methods: true},
+			want: "TestService_Get,TestOther_Get",
+		},
+		{
+			name: "functions matching the regexp",
+			opt:  Options{Functions: []string{"/^(Get|help)/"}, Funcs: true},
+			want: "TestGetAll,Test_helper",
+		},
+		{
+			name:    "nothing matches",
+			opt:     Options{All: true, Exported: true, Funcs: true, ReceiverType: "Service"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			opt:     Options{Functions: []string{"/(/"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opt.Template = "{{ $func := .Func }}\nfunc {{ $func.TestName }}(t *testing.T) {}\n"
+
+			g, err := NewGenerator(tt.opt, strings.NewReader(src), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGenerator error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := testNames(g.Funcs()); got != tt.want {
+				t.Errorf("Generator.Funcs = %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package gounit
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+//existingTests are the tests declared in the test file
+type existingTests struct {
+	//funcs are names of the top-level functions, i.e. tests and benchmarks
+	funcs map[string]bool
+
+	//suites are names of the test methods of the testify suites by the name
+	//of the tested type, i.e. "TestGet" method of the ServiceSuite is stored
+	//as suites["Service"]["TestGet"]
+	suites map[string]map[string]bool
+
+	//subtests are names of the subtests run with t.Run by the name of the top-level test
+	subtests map[string]map[string]bool
+}
+
+//findExistingTests collects the top-level tests, methods of the test suites
+//and the subtests declared in the file
+func findExistingTests(file *ast.File) existingTests {
+	tests := existingTests{
+		funcs:    map[string]bool{},
+		suites:   map[string]map[string]bool{},
+		subtests: map[string]map[string]bool{},
+	}
+
+	for _, test := range findFunctions(file.Decls, func(*ast.FuncDecl) bool { return true }) {
+		if !test.IsMethod() {
+			tests.funcs[test.Name()] = true
+			tests.subtests[test.Name()] = findSubtests(test.Signature.Body)
+			continue
+		}
+
+		if !strings.HasPrefix(test.Name(), "Test") {
+			continue
+		}
+
+		suite := suiteType(test.ReceiverTypeName())
+		if tests.suites[suite] == nil {
+			tests.suites[suite] = map[string]bool{}
+		}
+		tests.suites[suite][test.Name()] = true
+	}
+
+	return tests
+}
+
+//suiteType returns a name of the type tested by the suite, i.e.
+//"Service" for the ServiceSuite and the ServiceTestSuite
+func suiteType(suite string) string {
+	suite = strings.TrimSuffix(suite, "Suite")
+	return strings.TrimSuffix(suite, "Test")
+}
+
+//findSubtests returns names of the subtests run with the string
+//literal names, i.e. t.Run("Method", func(t *testing.T) {...})
+func findSubtests(body *ast.BlockStmt) map[string]bool {
+	names := map[string]bool{}
+	if body == nil {
+		return names
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Run" {
+			return true
+		}
+
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if name, err := strconv.Unquote(lit.Value); err == nil {
+				names[name] = true
+			}
+		}
+
+		return true
+	})
+
+	return names
+}
+
+//covers returns true if there is a test of the function: a top-level test named
+//in any of the styles returned by Func.testNames, a test method of the suite of
+//the method receiver type or a subtest named after the method in the test of its
+//receiver type, i.e. t.Run("Method", ...) in TestT
+func (tests existingTests) covers(f *Func) bool {
+	names := f.testNames()
+	for _, name := range names {
+		if tests.funcs[name] {
+			return true
+		}
+	}
+
+	if !f.IsMethod() {
+		return false
+	}
+
+	name := f.Name()
+	t := f.ReceiverTypeName()
+
+	suiteNames := append(names, "Test"+name, "Test_"+name)
+	subtestNames := []string{name, t + "." + name, t + "_" + name}
+	if !f.hasExportedTwin() {
+		title := strings.ToUpper(name[:1]) + name[1:]
+		suiteNames = append(suiteNames, "Test"+title)
+		subtestNames = append(subtestNames, title)
+	}
+
+	suite := tests.suites[t]
+	for _, n := range suiteNames {
+		if suite[n] {
+			return true
+		}
+	}
+
+	for _, test := range []string{"Test" + t, "Test_" + t} {
+		subtests := tests.subtests[test]
+		for _, n := range subtestNames {
+			if subtests[n] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
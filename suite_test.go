@@ -0,0 +1,96 @@
+package gounit
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func Test_suiteType(t *testing.T) {
+	tests := []struct {
+		suite string
+		want1 string
+	}{
+		{suite: "ServiceSuite", want1: "Service"},
+		{suite: "ServiceTestSuite", want1: "Service"},
+		{suite: "Service", want1: "Service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.suite, func(t *testing.T) {
+			if got1 := suiteType(tt.suite); got1 != tt.want1 {
+				t.Errorf("suiteType got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestExistingTests_covers(t *testing.T) {
+	const testSrc = `package p
+
+type ServiceSuite struct{ suite.Suite }
+
+func (s *ServiceSuite) TestGet() {}
+
+func (s *ServiceSuite) helper() {}
+
+type StoreTestSuite struct{ suite.Suite }
+
+func (s *StoreTestSuite) Test_load() {}
+
+func (s *StoreTestSuite) TestParse() {}
+
+func TestCache(t *testing.T) {
+	t.Run("Put", func(t *testing.T) {})
+	t.Run(name, func(t *testing.T) {})
+
+	for _, tt := range tests {
+		t.Run("Cache.Evict", func(t *testing.T) {})
+	}
+}
+
+func TestStore_Save(t *testing.T) {}
+`
+
+	fs := token.NewFileSet()
+	file, err := parser.ParseFile(fs, "file_test.go", testSrc, 0)
+	if err != nil {
+		t.Fatalf("failed to parse test file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		decl     string
+		declared map[string]bool
+
+		want1 bool
+	}{
+		{name: "suite method", decl: "func (s *Service) Get() {}", want1: true},
+		{name: "no suite method", decl: "func (s *Service) Set() {}", want1: false},
+		{name: "unexported method of the suite", decl: "func (s *Service) helper() {}", want1: false},
+		{name: "suite method of the unexported method", decl: "func (s Store) load() {}", want1: true},
+		{name: "suite method of the function", decl: "func Parse() {}", want1: false},
+		{name: "suite method of another type", decl: "func (c *Cache) Get() {}", want1: false},
+		{name: "subtest", decl: "func (c *Cache) Put() {}", want1: true},
+		{name: "qualified subtest", decl: "func (c *Cache) Evict() {}", want1: true},
+		{name: "subtest of another type", decl: "func (s *Service) Put() {}", want1: false},
+		{name: "top-level test", decl: "func (s Store) Save() {}", want1: true},
+		{name: "top-level test of the exported twin", decl: "func (s Store) save() {}", declared: map[string]bool{"Store.Save": true}, want1: false},
+		{name: "suite method of the exported twin", decl: "func (s *Service) get() {}", declared: map[string]bool{"Service.Get": true}, want1: false},
+		{name: "subtest of the exported twin", decl: "func (c *Cache) put() {}", declared: map[string]bool{"Cache.Put": true}, want1: false},
+		{name: "subtest of the unexported method", decl: "func (c *Cache) put() {}", want1: true},
+	}
+
+	existing := findExistingTests(file)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parseFunc(t, token.NewFileSet(), tt.decl)
+			f.declared = tt.declared
+
+			if got1 := existing.covers(f); got1 != tt.want1 {
+				t.Errorf("existingTests.covers got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
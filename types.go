@@ -0,0 +1,146 @@
+package gounit
+
+import (
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//typeCheck type-checks the files of the source package.
+//Type checking is done on the best effort basis: type errors are ignored
+//so the resulting info may be incomplete if the package doesn't compile
+func typeCheck(fs *token.FileSet, files []*ast.File) (*types.Package, *types.Info) {
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fs, "source", nil),
+		Error:    func(error) {},
+	}
+
+	pkg, _ := conf.Check(files[0].Name.Name, fs, files, info)
+
+	return pkg, info
+}
+
+//parsePackageFiles parses all non-test files of the package pkgName located in
+//the directory dir except the file named exclude. If pkgName is empty the
+//package name of the first parsed file is used
+func parsePackageFiles(fs *token.FileSet, pkgName, dir, exclude string) []*ast.File {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []*ast.File
+	for _, fi := range infos {
+		name := fi.Name()
+		if fi.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == exclude {
+			continue
+		}
+
+		if match, err := build.Default.MatchFile(dir, name); err != nil || !match {
+			continue
+		}
+
+		f, err := parser.ParseFile(fs, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		if pkgName == "" {
+			pkgName = f.Name.Name
+		}
+
+		if f.Name.Name == pkgName {
+			files = append(files, f)
+		}
+	}
+
+	return files
+}
+
+//importPath returns an import path of the package located in the directory dir.
+//It's resolved using the module path from the nearest go.mod file or GOPATH
+func importPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ErrImportPath.Format(err)
+	}
+
+	for d := abs; ; d = filepath.Dir(d) {
+		if b, err := ioutil.ReadFile(filepath.Join(d, "go.mod")); err == nil {
+			if module := modulePath(b); module != "" {
+				rel, err := filepath.Rel(d, abs)
+				if err != nil {
+					return "", ErrImportPath.Format(err)
+				}
+
+				return path.Join(module, filepath.ToSlash(rel)), nil
+			}
+		}
+
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	pkg, err := build.Default.ImportDir(abs, build.FindOnly)
+	if err != nil {
+		return "", ErrImportPath.Format(err)
+	}
+
+	if pkg.ImportPath == "" || pkg.ImportPath == "." {
+		return "", ErrImportPath.Format("directory is outside of GOPATH and modules")
+	}
+
+	return pkg.ImportPath, nil
+}
+
+//modulePath returns a module path declared in the go.mod file contents
+func modulePath(gomod []byte) string {
+	for _, line := range strings.Split(string(gomod), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module")), `"`)
+		}
+	}
+
+	return ""
+}
+
+//lookupInterface looks up an interface by its name. The name can be either a predeclared
+//"error" interface, a name of the interface declared in the package pkg or a qualified
+//name of the interface declared in one of the packages imported by pkg, i.e. "io.Reader"
+func lookupInterface(pkg *types.Package, name string) *types.Interface {
+	var obj types.Object
+
+	if i := strings.LastIndex(name, "."); i > 0 {
+		pkgName, typeName := name[:i], name[i+1:]
+		for _, imported := range pkg.Imports() {
+			if imported.Path() == pkgName || imported.Name() == pkgName {
+				obj = imported.Scope().Lookup(typeName)
+				break
+			}
+		}
+	} else if obj = types.Universe.Lookup(name); obj == nil {
+		obj = pkg.Scope().Lookup(name)
+	}
+
+	if _, ok := obj.(*types.TypeName); !ok {
+		return nil
+	}
+
+	iface, _ := obj.Type().Underlying().(*types.Interface)
+	return iface
+}
@@ -0,0 +1,97 @@
+package gounit
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+//typedFuncs parses and type-checks the source and returns all functions
+//declared in it with the type information attached
+func typedFuncs(t *testing.T, src string) []*Func {
+	fs := token.NewFileSet()
+	file, err := parser.ParseFile(fs, "typed.go", src, 0)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+
+	_, info := typeCheck(fs, []*ast.File{file})
+
+	funcs := findFunctions(file.Decls, func(*ast.FuncDecl) bool { return true })
+	for _, f := range funcs {
+		f.Object, _ = info.Defs[f.Signature.Name].(*types.Func)
+	}
+
+	return funcs
+}
+
+func Test_typeCheck(t *testing.T) {
+	funcs := typedFuncs(t, `package typed
+
+	import "io"
+
+	type T struct{}
+
+	func (t *T) Read(p []byte) (int, error) { return 0, nil }
+
+	func f(r io.Reader, ts ...T) *T { return nil }`)
+
+	if len(funcs) != 2 {
+		t.Fatalf("unexpected number of funcs: %d", len(funcs))
+	}
+
+	for _, f := range funcs {
+		if !f.IsTyped() {
+			t.Errorf("%s is not typed", f.Name())
+		}
+	}
+
+	params := funcs[1].ParamTypes()
+	if len(params) != 2 {
+		t.Fatalf("unexpected number of params: %d", len(params))
+	}
+
+	if s := params[1].String(); s != "[]typed.T" {
+		t.Errorf("unexpected variadic param type: %s", s)
+	}
+}
+
+func Test_lookupInterface(t *testing.T) {
+	funcs := typedFuncs(t, `package typed
+
+	import "io"
+
+	type Iface interface{ Method() }
+
+	type NotIface struct{}
+
+	func f(r io.Reader) {}`)
+
+	pkg := funcs[0].Object.Pkg()
+
+	tests := []struct {
+		name  string
+		iface string
+
+		want1 bool
+	}{
+		{name: "predeclared", iface: "error", want1: true},
+		{name: "local", iface: "Iface", want1: true},
+		{name: "imported", iface: "io.Reader", want1: true},
+		{name: "not an interface", iface: "NotIface"},
+		{name: "not imported", iface: "fmt.Stringer"},
+		{name: "missing", iface: "Missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := lookupInterface(pkg, tt.iface) != nil
+
+			if got1 != tt.want1 {
+				t.Errorf("lookupInterface got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package gounit
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+//testUpdate is an existing test that doesn't match the signature of the tested function
+type testUpdate struct {
+	f    *Func
+	test *ast.FuncDecl
+}
+
+//findStaleTests returns updates of the generated tests declared in the file
+//that don't match the signatures of the tested functions
+func findStaleTests(fs *token.FileSet, file *ast.File, funcs []*Func, qualifier string) []testUpdate {
+	tests := map[string]*ast.FuncDecl{}
+	for _, test := range findFunctions(file.Decls, isGeneratedTest) {
+		tests[test.Name()] = test.Signature
+	}
+
+	var updates []testUpdate
+	for _, f := range funcs {
+		test, ok := tests[f.TestName()]
+		if !ok {
+			continue
+		}
+
+		if len(f.checkTest(fs, test, qualifier)) > 0 {
+			updates = append(updates, testUpdate{f: f, test: test})
+		}
+	}
+
+	return updates
+}
+
+//updatedFuncs returns functions of the updated tests
+func updatedFuncs(updates []testUpdate) []*Func {
+	var funcs []*Func
+	for _, u := range updates {
+		funcs = append(funcs, u.f)
+	}
+
+	return funcs
+}
+
+//applyUpdates replaces the stale tests in the source of the test file with the tests
+//rendered by the test template. Test cases of the stale tests are preserved except
+//for the values of the args and want fields that no longer exist
+func (g *Generator) applyUpdates() error {
+	src := g.buf.Bytes()
+
+	//updates are applied from the end of the file so the offsets of the rest of the tests remain valid
+	updates := append([]testUpdate{}, g.updates...)
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].test.Pos() > updates[j].test.Pos()
+	})
+
+	for _, u := range updates {
+		rendered := bytes.NewBuffer([]byte{})
+		if err := g.writeTest(rendered, u.f); err != nil {
+			return err
+		}
+
+		test, err := g.spliceTestCases(u, rendered.String())
+		if err != nil {
+			return fmt.Errorf("%s: %v", u.f.TestName(), err)
+		}
+
+		start, end := g.fs.Position(u.test.Pos()).Offset, g.fs.Position(u.test.End()).Offset
+
+		updated := append([]byte{}, src[:start]...)
+		updated = append(updated, test...)
+		src = append(updated, src[end:]...)
+	}
+
+	g.buf = bytes.NewBuffer(src)
+
+	return nil
+}
+
+//spliceTestCases replaces the test cases of the rendered test
+//with the test cases of the existing test
+func (g *Generator) spliceTestCases(u testUpdate, rendered string) (string, error) {
+	const header = "package p\n"
+
+	fs := token.NewFileSet()
+	file, err := parser.ParseFile(fs, "", header+rendered, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse rendered test: %v", err)
+	}
+
+	var test *ast.FuncDecl
+	for _, f := range findFunctions(file.Decls, isGeneratedTest) {
+		if f.Name() == u.f.TestName() {
+			test = f.Signature
+		}
+	}
+
+	if test == nil {
+		return "", fmt.Errorf("rendered test doesn't declare test cases")
+	}
+
+	newCases := findTestCasesLit(test.Body)
+	oldCases := findTestCasesLit(u.test.Body)
+
+	var (
+		src      = g.buf.Bytes()
+		oldStart = g.fs.Position(oldCases.Lbrace).Offset + 1
+		oldEnd   = g.fs.Position(oldCases.Rbrace).Offset
+		newStart = fs.Position(newCases.Lbrace).Offset + 1 - len(header)
+		newEnd   = fs.Position(newCases.Rbrace).Offset - len(header)
+		funcPos  = fs.Position(test.Pos()).Offset - len(header)
+		funcEnd  = fs.Position(test.End()).Offset - len(header)
+	)
+
+	//values of the removed fields are removed from the test cases so they compile
+	removed := removedFields(findTestCasesType(u.test.Body), findTestCasesType(test.Body))
+	removedArgs := removedFields(findStructType(u.test.Body, "args"), findStructType(test.Body, "args"))
+
+	var (
+		cut [][2]int
+
+		//values of the removed fields are cut as a whole, i.e. the fields
+		//of the args literal aren't cut again when the args field is removed
+		removedValues = map[ast.Node]bool{}
+	)
+
+	ast.Inspect(oldCases, func(n ast.Node) bool {
+		if removedValues[n] {
+			return false
+		}
+
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		fields := removed
+		if ident, ok := lit.Type.(*ast.Ident); ok && ident.Name == "args" {
+			fields = removedArgs
+		} else if lit.Type != nil {
+			return true
+		}
+
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			if key, ok := kv.Key.(*ast.Ident); ok && fields[key.Name] {
+				cut = append(cut, lineRange(src, g.fs.Position(kv.Pos()).Offset, g.fs.Position(kv.End()).Offset))
+				removedValues[kv] = true
+			}
+		}
+
+		return true
+	})
+
+	//nested literals are inspected after the fields of the enclosing literal
+	sort.Slice(cut, func(i, j int) bool { return cut[i][0] < cut[j][0] })
+
+	cases := string(src[oldStart:oldEnd])
+	for i := len(cut) - 1; i >= 0; i-- {
+		cases = cases[:cut[i][0]-oldStart] + cases[cut[i][1]-oldStart:]
+	}
+
+	return strings.Join([]string{
+		rendered[funcPos:newStart],
+		cases,
+		rendered[newEnd:funcEnd],
+	}, ""), nil
+}
+
+//removedFields returns names of the fields of the old struct that
+//are not declared in the new struct
+func removedFields(oldStruct, newStruct *ast.StructType) map[string]bool {
+	removed := map[string]bool{}
+	if oldStruct == nil {
+		return removed
+	}
+
+	for _, field := range oldStruct.Fields.List {
+		for _, name := range field.Names {
+			removed[name.Name] = true
+		}
+	}
+
+	if newStruct != nil {
+		for _, field := range newStruct.Fields.List {
+			for _, name := range field.Names {
+				delete(removed, name.Name)
+			}
+		}
+	}
+
+	return removed
+}
+
+//lineRange extends the range of the key-value expression to the trailing comma
+//and to the whole line if the expression is the only one on the line
+func lineRange(src []byte, start, end int) [2]int {
+	if end < len(src) && src[end] == ',' {
+		end++
+	}
+
+	lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+	lineEnd := bytes.IndexByte(src[end:], '\n')
+
+	if lineEnd >= 0 && len(bytes.TrimSpace(src[lineStart:start])) == 0 && len(bytes.TrimSpace(src[end:end+lineEnd])) == 0 {
+		return [2]int{lineStart, end + lineEnd + 1}
+	}
+
+	return [2]int{start, end}
+}
@@ -0,0 +1,196 @@
+package gounit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const updateTemplate = `{{$func := .Func}}
+func {{ $func.TestName }}(t *testing.T) {
+	type args struct {
+		{{ range $param := params $func }}{{ $param }}
+		{{ end }}
+	}
+	tests := []struct {
+		name string
+		args args
+		{{ range $result := results $func }}{{ want $result }}
+		{{ end }}
+	}{
+		//TODO: Add test cases
+	}
+
+	for _, tt := range tests {
+		{{ join $func.ResultsNames ", " }} := {{ $func.Name }}({{ range $pn := $func.ParamsNames }}tt.args.{{ $pn }}, {{ end }})
+		_ = got1
+	}
+}
+`
+
+const updateTestSource = `package update
+
+import "testing"
+
+//TestSum is a test with hand-written cases
+func TestSum(t *testing.T) {
+	type args struct {
+		a int
+		b int
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want1 int
+		want2 bool
+	}{
+		//hand-written test case
+		{name: "one", args: args{a: 1, b: 2}, want1: 3, want2: true},
+		{
+			name:  "two",
+			args:  args{a: 2, b: 2},
+			want1: 4,
+			want2: false,
+		},
+	}
+
+	for _, tt := range tests {
+		got1, got2 := Sum(tt.args.a, tt.args.b)
+		_, _ = got1, got2
+	}
+}
+
+func TestUnchanged(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want1 string
+	}{}
+
+	for _, tt := range tests {
+		got1 := Unchanged(tt.args.s)
+		_ = got1
+	}
+}
+`
+
+func TestGenerator_Write_update(t *testing.T) {
+	src := `package update
+
+func Sum(a int, c int64) int { return 0 }
+
+func Unchanged(s string) string { return s }
+`
+
+	opt := Options{
+		All:        true,
+		Update:     true,
+		InputFile:  "update.go",
+		OutputFile: "update_test.go",
+		Template:   updateTemplate,
+	}
+
+	g, err := NewGenerator(opt, strings.NewReader(src), strings.NewReader(updateTestSource))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	if got := testNames(g.Updated()); got != "TestSum" {
+		t.Fatalf("Generator.Updated = %v, want: TestSum", got)
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := g.Write(buf); err != nil {
+		t.Fatalf("Generator.Write: %v", err)
+	}
+
+	got1 := buf.String()
+
+	for _, want := range []string{
+		"TestSum is a test with hand-written cases",
+		"\t\ta int\n\t\tc int64\n",
+		"//hand-written test case",
+		`{name: "one", args: args{a: 1}, want1: 3}`,
+		"got1 := Sum(tt.args.a, tt.args.c)",
+		"func TestUnchanged(t *testing.T) {",
+	} {
+		if !strings.Contains(got1, want) {
+			t.Errorf("updated test doesn't contain %q:\n%s", want, got1)
+		}
+	}
+
+	for _, unwanted := range []string{"want2", "b: 2", "TODO"} {
+		if strings.Contains(got1, unwanted) {
+			t.Errorf("updated test contains %q:\n%s", unwanted, got1)
+		}
+	}
+}
+
+func TestGenerator_Write_update_allParamsRemoved(t *testing.T) {
+	const template = `{{$func := .Func}}
+func {{ $func.TestName }}(t *testing.T) {
+	{{- if (gt $func.NumParams 0) }}
+	type args struct {
+		{{ range $param := params $func }}{{ $param }}
+		{{ end }}
+	}
+	{{- end }}
+	tests := []struct {
+		name string
+		{{- if (gt $func.NumParams 0) }}
+		args args
+		{{- end }}
+		{{ range $result := results $func }}{{ want $result }}
+		{{ end }}
+	}{
+		//TODO: Add test cases
+	}
+
+	for _, tt := range tests {
+		{{ join $func.ResultsNames ", " }} := {{ $func.Name }}({{ range $pn := $func.ParamsNames }}tt.args.{{ $pn }}, {{ end }})
+		_ = got1
+	}
+}
+`
+
+	opt := Options{
+		All:        true,
+		Update:     true,
+		InputFile:  "update.go",
+		OutputFile: "update_test.go",
+		Template:   template,
+	}
+
+	src := "package update\n\nfunc Sum() (int, bool) { return 0, false }\n\nfunc Unchanged(s string) string { return s }\n"
+
+	g, err := NewGenerator(opt, strings.NewReader(src), strings.NewReader(updateTestSource))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := g.Write(buf); err != nil {
+		t.Fatalf("Generator.Write: %v", err)
+	}
+
+	got1 := buf.String()
+
+	for _, want := range []string{
+		`{name: "one", want1: 3, want2: true}`,
+		"name:  \"two\",\n\t\t\twant1: 4,\n\t\t\twant2: false,\n",
+		"got1, got2 := Sum()",
+	} {
+		if !strings.Contains(got1, want) {
+			t.Errorf("updated test doesn't contain %q:\n%s", want, got1)
+		}
+	}
+
+	for _, unwanted := range []string{"args", "a: 1", "b: 2"} {
+		if strings.Contains(got1[:strings.Index(got1, "func TestUnchanged")], unwanted) {
+			t.Errorf("updated test contains %q:\n%s", unwanted, got1)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package gounit
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+var (
+	ErrTemplateCheckFailed = GenericError("template check failed for %d of %d case(s)")
+)
+
+//Statuses of the template checks
+const (
+	//CheckPassed means that the generated code parses and type-checks
+	CheckPassed = "ok"
+
+	//CheckFailed means that the template can't be executed or the generated code
+	//doesn't parse or type-check
+	CheckFailed = "FAIL"
+
+	//CheckSkipped means that the generated code parses but it can't be type-checked
+	//because the packages imported by the generated code can't be found
+	CheckSkipped = "skip"
+)
+
+//TemplateCheck is a result of the template check against a case of the corpus
+type TemplateCheck struct {
+	Kind   Kind
+	Case   string
+	Status string
+	Err    error
+}
+
+//String implements fmt.Stringer
+func (c TemplateCheck) String() string {
+	s := fmt.Sprintf("%-4s %s: %s", c.Status, c.Kind, c.Case)
+	if c.Err != nil {
+		s += ": " + c.Err.Error()
+	}
+
+	return s
+}
+
+//templateCase is a source of the package with tricky signatures
+type templateCase struct {
+	name string
+	src  string
+}
+
+//templateCorpus is a list of the cases every template is checked against
+var templateCorpus = []templateCase{
+	{"function", "func function() int { return 0 }"},
+	{"no results", "func noResults(s string) {}"},
+	{"multiple params of the same type", "func sum(a, b int) int { return a + b }"},
+	{"multiple results", "func split(s string) (string, string, bool) { return s, s, true }"},
+	{"error result", "func parse(s string) (int, error) { return 0, nil }"},
+	{"only error result", "func closer() error { return nil }"},
+	{"named results", "func div(a, b float64) (q float64, err error) { return a / b, nil }"},
+	{"variadic function", "func join(sep string, parts ...string) string { return sep }"},
+	{"interface params", "func copyAll(w io.Writer, r io.Reader) (int64, error) { return io.Copy(w, r) }"},
+	{"composite types", "func apply(m map[string][]int, c chan<- int, f func(int) bool, p *[2]byte) {}"},
+	{"struct params", "type Config struct{ N int }\n\nfunc run(c Config, p *Config) error { return nil }"},
+	{"method with pointer receiver", "type T struct{ n int }\n\nfunc NewT(n int) *T { return &T{n: n} }\n\nfunc (t *T) Add(n int) int { return t.n + n }"},
+	{"method with value receiver", "type V struct{ s string }\n\nfunc (v V) String() string { return v.s }"},
+	{"method with constructor returning error", "type C struct{}\n\nfunc NewC(s string) (*C, error) { return &C{}, nil }\n\nfunc (c *C) Do() (string, error) { return \"\", nil }"},
+	{"generic function", "func mapSlice[T any, R any](s []T, f func(T) R) []R { return nil }"},
+	{"method of generic type", "type Stack[T comparable] struct{ items []T }\n\nfunc (s *Stack[T]) Push(v T) bool { return true }"},
+}
+
+//CheckTemplate executes the template against the corpus of tricky signatures and
+//verifies that the generated code parses and type-checks. Besides the test template
+//the templates of the other kinds are checked if they're defined by the template,
+//i.e. {{define "bench"}}...{{end}}
+func CheckTemplate(tmpl string) ([]TemplateCheck, error) {
+	_, body, err := ParseTemplate(tmpl)
+	if err != nil {
+		return nil, ErrInvalidTestTemplate.Format(err)
+	}
+
+	parsed, err := template.New("test").Funcs(templateHelpers(token.NewFileSet())).Parse(body)
+	if err != nil {
+		return nil, ErrInvalidTestTemplate.Format(err)
+	}
+
+	kinds := []Kind{KindTest}
+	for _, kind := range []Kind{KindBenchmark, KindFuzz, KindExample} {
+		if parsed.Lookup(string(kind)) != nil {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	//generator looks for the existing tests in the directory of the output file
+	dir, err := ioutil.TempDir("", "gounit_corpus")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	fs := token.NewFileSet()
+	imp := importer.ForCompiler(fs, "source", nil)
+
+	var checks []TemplateCheck
+	for _, kind := range kinds {
+		for _, c := range templateCorpus {
+			check := TemplateCheck{Kind: kind, Case: c.name, Status: CheckPassed}
+			check.Err = checkCase(fs, imp, dir, tmpl, kind, c)
+
+			switch check.Err.(type) {
+			case nil:
+			case unresolvedImportError:
+				check.Status = CheckSkipped
+			default:
+				check.Status = CheckFailed
+			}
+
+			checks = append(checks, check)
+		}
+	}
+
+	return checks, nil
+}
+
+//unresolvedImportError is returned by checkCase when the generated code
+//imports the packages that can't be found
+type unresolvedImportError struct {
+	error
+}
+
+//checkCase generates the code for the case and type-checks it along with the source
+func checkCase(fs *token.FileSet, imp types.Importer, dir, tmpl string, kind Kind, c templateCase) error {
+	src := "package corpus\n\nimport \"io\"\n\nvar _ io.Reader\n\n" + c.src + "\n"
+
+	opt := Options{
+		All:        true,
+		InputFile:  filepath.Join(dir, "corpus.go"),
+		OutputFile: filepath.Join(dir, "corpus_test.go"),
+		Template:   tmpl,
+		Kind:       kind,
+	}
+
+	g, err := NewGenerator(opt, strings.NewReader(src), nil)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := g.Write(buf); err != nil {
+		return fmt.Errorf("%s", strings.Replace(err.Error(), dir+string(filepath.Separator), "", -1))
+	}
+
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	srcFile, err := parser.ParseFile(fs, opt.InputFile, src, 0)
+	if err != nil {
+		return err
+	}
+
+	testFile, err := parser.ParseFile(fs, opt.OutputFile, buf.Bytes(), 0)
+	if err != nil {
+		return fmt.Errorf("generated code doesn't parse: %v", err)
+	}
+
+	var (
+		typeErrors, importErrors []string
+		lines                    = strings.Split(buf.String(), "\n")
+	)
+
+	conf := types.Config{
+		Importer: imp,
+		Error: func(err error) {
+			msg := err.Error()
+			if te, ok := err.(types.Error); ok {
+				//generated file is removed so the offending line is reported along with the error
+				if pos := fs.Position(te.Pos); pos.Filename == opt.OutputFile && pos.Line <= len(lines) {
+					pos.Filename = filepath.Base(pos.Filename)
+					msg = fmt.Sprintf("%s: %s\n\t%s", pos, shortMessage(te.Msg), strings.TrimSpace(lines[pos.Line-1]))
+				}
+			}
+
+			if strings.Contains(msg, "could not import") {
+				importErrors = append(importErrors, msg)
+			} else {
+				typeErrors = append(typeErrors, msg)
+			}
+		},
+	}
+
+	conf.Check("corpus", fs, []*ast.File{srcFile, testFile}, nil)
+
+	//identifiers of the packages that can't be imported are reported as undefined
+	//so the type errors can't be trusted when some of the imports are unresolved
+	switch {
+	case len(importErrors) > 0:
+		return unresolvedImportError{fmt.Errorf("type-check skipped: %s", importErrors[0])}
+	case len(typeErrors) > 0:
+		return fmt.Errorf("generated code doesn't type-check: %s", typeErrors[0])
+	}
+
+	return nil
+}
+
+//shortMessage returns the first line of the multiline message without
+//the details of the import errors, i.e. the list of the searched directories
+func shortMessage(msg string) string {
+	if i := strings.Index(msg, "\n"); i >= 0 {
+		msg = msg[:i]
+	}
+
+	if i := strings.Index(msg, " ("); i >= 0 && strings.HasPrefix(msg, "could not import") {
+		msg = msg[:i]
+	}
+
+	return msg
+}
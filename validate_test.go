@@ -0,0 +1,89 @@
+package gounit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckTemplate(t *testing.T) {
+	const validTemplate = `{{ $func := .Func }}
+func {{ $func.TestName }}(t *testing.T) {
+	{{- if $func.IsGeneric }}
+		type (
+			{{- range $alias := typeAliases $func }}
+				{{ $alias }}
+			{{- end }}
+		)
+	{{ end -}}
+	{{ if $func.IsMethod }}receiver := func() {{ ast $func.ReceiverType }} { {{ construct $func }} }()
+	{{ end -}}
+	{{ if (gt $func.NumResults 0) }}{{ join $func.ResultsNames ", " }} := {{ end -}}
+	{{ if $func.IsMethod }}receiver.{{ $func.Name }}{{ else }}{{ $func.QualifiedName }}{{ $func.TypeParamsList }}{{ end }}(
+		{{- range $i, $param := params $func }}{{ if $i }}, {{ end }}{{ zero $func $param }}{{ end }}
+		{{- if $func.IsVariadic }}...{{ end }})
+	{{- range $name := $func.ResultsNames }}
+		_ = {{ $name }}
+	{{- end }}
+}
+`
+
+	tests := []struct {
+		name string
+		tmpl string
+
+		wantStatus map[string]int
+		wantErr    bool
+	}{
+		{
+			name:       "valid template",
+			tmpl:       validTemplate,
+			wantStatus: map[string]int{CheckPassed: len(templateCorpus)},
+		},
+		{
+			name:       "valid template with benchmarks",
+			tmpl:       validTemplate + `{{ define "bench" }}{{ end }}`,
+			wantStatus: map[string]int{CheckPassed: 2 * len(templateCorpus)},
+		},
+		{
+			name:       "calls without arguments",
+			tmpl:       "{{ $func := .Func }}\nfunc {{ $func.TestName }}(t *testing.T) { {{ $func.Name }}() }\n",
+			wantStatus: map[string]int{CheckPassed: 2, CheckFailed: len(templateCorpus) - 2},
+		},
+		{
+			name: "unresolved import",
+			tmpl: "---\nimports: [example.com/unresolved/assert]\n---\n" +
+				"{{ $func := .Func }}\nfunc {{ $func.TestName }}(t *testing.T) { assert.True(t, true) }\n",
+			wantStatus: map[string]int{CheckSkipped: len(templateCorpus)},
+		},
+		{
+			name:    "invalid template",
+			tmpl:    "{{ .Func.TestName ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks, err := CheckTemplate(tt.tmpl)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckTemplate error = %v, wantErr: %t", err, tt.wantErr)
+			}
+
+			got := map[string]int{}
+			var failures []string
+			for _, c := range checks {
+				got[c.Status]++
+				if c.Status != CheckPassed {
+					failures = append(failures, c.String())
+				}
+			}
+
+			for status, want := range tt.wantStatus {
+				if got[status] != want {
+					t.Errorf("CheckTemplate returned %d %q checks, want: %d\n%s", got[status], status, want, strings.Join(failures, "\n"))
+				}
+			}
+		})
+	}
+}
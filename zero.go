@@ -0,0 +1,372 @@
+package gounit
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+//ZeroValue returns a compilable literal of the zero value of the type, i.e. 0, "", nil, T{},
+//map[K]V{} or make(chan T). Pointers to structs are initialized with &T{} rather than nil and
+//interfaces that have fakes generated by MockGenerator are initialized with &TMock{}.
+//The type can be passed as an ast.Expr, a types.Type, a string containing a type
+//expression or a param/result declaration returned by Params and Results.
+//When it's not possible to tell the kind of the named type "*new(T)" is returned
+func (f *Func) ZeroValue(fs *token.FileSet, typ interface{}) string {
+	switch t := typ.(type) {
+	case types.Type:
+		return typedZero(t, f.qualifier())
+	case ast.Expr:
+		return f.zero(fs, t)
+	case string:
+		//declaration like "got1 int"
+		if i := strings.Index(t, " "); i > 0 && token.IsIdentifier(t[:i]) {
+			t = t[i+1:]
+		}
+
+		expr, err := parser.ParseExpr(t)
+		if err != nil {
+			return "nil"
+		}
+
+		return f.zero(token.NewFileSet(), expr)
+	}
+
+	return "nil"
+}
+
+//zero returns a zero value literal for the type expression
+func (f *Func) zero(fs *token.FileSet, expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if zero, ok := predeclaredZero(t.Name); ok {
+			return zero
+		}
+
+		for _, tp := range f.typeParams() {
+			if tp.name == t.Name {
+				return f.typeParamZero(fs, tp)
+			}
+		}
+
+		if spec := f.typeSpecs[t.Name]; spec != nil && spec.TypeParams == nil {
+			//fake implementation of the interface generated by MockGenerator
+			if _, isInterface := spec.Type.(*ast.InterfaceType); isInterface && f.testTypes[t.Name+"Mock"] {
+				return "&" + t.Name + "Mock{}"
+			}
+
+			return f.namedZero(fs, t, spec.Type)
+		}
+
+		if f.Object != nil {
+			if obj, ok := f.Object.Pkg().Scope().Lookup(t.Name).(*types.TypeName); ok {
+				return typedZero(obj.Type(), f.qualifier())
+			}
+		}
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		//instantiated generic type
+		if spec := f.localTypeSpec(t); spec != nil {
+			return f.namedZero(fs, t, spec.Type)
+		}
+	case *ast.SelectorExpr:
+		//type of the source package referenced from the external test package
+		if spec := f.localTypeSpec(t); spec != nil && spec.TypeParams == nil {
+			return f.namedZero(fs, t, spec.Type)
+		}
+
+		if obj := f.importedType(t); obj != nil {
+			return typedZero(obj.Type(), f.qualifier())
+		}
+	case *ast.ParenExpr:
+		return f.zero(fs, t.X)
+	case *ast.StarExpr:
+		//pointer to a struct declared in the source file is initialized
+		//to avoid nil pointer dereference in the tested code
+		if spec := f.localTypeSpec(t.X); spec != nil {
+			if _, isStruct := spec.Type.(*ast.StructType); isStruct {
+				return "&" + nodeToString(fs, t.X) + "{}"
+			}
+		}
+		return "nil"
+	case *ast.FuncType, *ast.InterfaceType, *ast.Ellipsis:
+		return "nil"
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "nil"
+		}
+		return nodeToString(fs, t) + "{}"
+	case *ast.MapType, *ast.StructType:
+		return nodeToString(fs, t) + "{}"
+	case *ast.ChanType:
+		return "make(" + nodeToString(fs, t) + ")"
+	}
+
+	return "*new(" + nodeToString(fs, expr) + ")"
+}
+
+//WantZero returns a literal of the zero value the result of the function is compared to
+//when it's not set: unlike ZeroValue it's nil for pointers, maps, slices, channels and
+//interfaces. An empty string is returned when it's not possible to tell the kind of the type
+func (f *Func) WantZero(fs *token.FileSet, typ interface{}) string {
+	switch t := typ.(type) {
+	case types.Type:
+		return typedWantZero(t, f.qualifier())
+	case ast.Expr:
+		return f.wantZero(fs, t)
+	case string:
+		if i := strings.Index(t, " "); i > 0 && token.IsIdentifier(t[:i]) {
+			t = t[i+1:]
+		}
+
+		expr, err := parser.ParseExpr(t)
+		if err != nil {
+			return ""
+		}
+
+		return f.wantZero(token.NewFileSet(), expr)
+	}
+
+	return ""
+}
+
+//wantZero returns a zero value literal of the result type, see WantZero
+func (f *Func) wantZero(fs *token.FileSet, expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.ParenExpr:
+		return f.wantZero(fs, t.X)
+	case *ast.StarExpr, *ast.FuncType, *ast.InterfaceType, *ast.MapType, *ast.ChanType:
+		return "nil"
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "nil"
+		}
+	case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		if spec := f.localTypeSpec(t); spec != nil && !isTypeParam(f, t) {
+			if f.nilable(spec.Type, map[string]bool{}) {
+				return "nil"
+			}
+		} else if obj := f.namedType(t); obj != nil {
+			return typedWantZero(obj.Type(), f.qualifier())
+		}
+	}
+
+	zero := f.zero(fs, expr)
+	if strings.HasPrefix(zero, "*new(") {
+		return ""
+	}
+
+	return zero
+}
+
+//namedType looks up the type referenced by the identifier or the selector
+//expression, it returns nil when the type information is not available
+func (f *Func) namedType(expr ast.Expr) *types.TypeName {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if f.Object != nil {
+			obj, _ := f.Object.Pkg().Scope().Lookup(t.Name).(*types.TypeName)
+			return obj
+		}
+	case *ast.SelectorExpr:
+		return f.importedType(t)
+	}
+
+	return nil
+}
+
+//isTypeParam returns true if the expression is a type parameter of the function
+func isTypeParam(f *Func, expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	for _, tp := range f.typeParams() {
+		if tp.name == ident.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+//nilable returns true if the zero value of the type expression is nil,
+//types declared in the source package are followed to their definitions
+func (f *Func) nilable(expr ast.Expr, seen map[string]bool) bool {
+	switch t := expr.(type) {
+	case *ast.StarExpr, *ast.FuncType, *ast.InterfaceType, *ast.MapType, *ast.ChanType:
+		return true
+	case *ast.ArrayType:
+		return t.Len == nil
+	case *ast.ParenExpr:
+		return f.nilable(t.X, seen)
+	case *ast.Ident:
+		if spec := f.typeSpecs[t.Name]; spec != nil && !seen[t.Name] {
+			seen[t.Name] = true
+			return f.nilable(spec.Type, seen)
+		}
+	}
+
+	return false
+}
+
+//typedWantZero returns a zero value literal of the result type, see WantZero
+func typedWantZero(t types.Type, qf types.Qualifier) string {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Map, *types.Chan, *types.Slice, *types.Signature:
+		return "nil"
+	case *types.Interface:
+		if _, isTypeParam := t.(*types.TypeParam); !isTypeParam {
+			return "nil"
+		}
+	case *types.Basic:
+		if u.Kind() == types.UnsafePointer || u.Kind() == types.UntypedNil {
+			return "nil"
+		}
+	}
+
+	zero := typedZero(t, qf)
+	if strings.HasPrefix(zero, "*new(") {
+		return ""
+	}
+
+	return zero
+}
+
+//namedZero returns a zero value literal of the named type with the given underlying type expression
+func (f *Func) namedZero(fs *token.FileSet, name, underlying ast.Expr) string {
+	switch u := underlying.(type) {
+	case *ast.StructType, *ast.MapType:
+		return nodeToString(fs, name) + "{}"
+	case *ast.ArrayType:
+		if u.Len != nil {
+			return nodeToString(fs, name) + "{}"
+		}
+		return "nil"
+	case *ast.ChanType:
+		return "make(" + nodeToString(fs, name) + ")"
+	case *ast.StarExpr, *ast.FuncType, *ast.InterfaceType:
+		return "nil"
+	case *ast.Ident:
+		if zero, ok := predeclaredZero(u.Name); ok {
+			return zero
+		}
+
+		//type defined as another type declared in the source file
+		if spec := f.typeSpecs[u.Name]; spec != nil && u.Name != baseTypeName(name) {
+			return f.namedZero(fs, name, spec.Type)
+		}
+	}
+
+	return "*new(" + nodeToString(fs, name) + ")"
+}
+
+//localTypeSpec returns a declaration of the source package type referenced by the
+//possibly instantiated type expression or nil if the type is declared elsewhere
+func (f *Func) localTypeSpec(expr ast.Expr) *ast.TypeSpec {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return f.typeSpecs[t.Name]
+	case *ast.SelectorExpr:
+		if f.isSourcePackage(t.X) {
+			return f.typeSpecs[t.Sel.Name]
+		}
+	case *ast.IndexExpr:
+		return f.localTypeSpec(t.X)
+	case *ast.IndexListExpr:
+		return f.localTypeSpec(t.X)
+	}
+
+	return nil
+}
+
+//typeParamZero returns a zero value literal of the placeholder type argument
+//the type parameter is bound to by the TypeAliases
+func (f *Func) typeParamZero(fs *token.FileSet, tp typeParam) string {
+	for _, alias := range f.TypeAliases(fs) {
+		if placeholder := strings.TrimPrefix(alias, tp.name+" = "); placeholder != alias {
+			if expr, err := parser.ParseExpr(placeholder); err == nil {
+				return f.zero(fs, expr)
+			}
+		}
+	}
+
+	return "*new(" + tp.name + ")"
+}
+
+//importedType looks up the type declared in the imported package, it
+//returns nil when the type information is not available
+func (f *Func) importedType(sel *ast.SelectorExpr) *types.TypeName {
+	pkgIdent, ok := sel.X.(*ast.Ident)
+	if !ok || f.Object == nil {
+		return nil
+	}
+
+	if f.isSourcePackage(pkgIdent) {
+		obj, _ := f.Object.Pkg().Scope().Lookup(sel.Sel.Name).(*types.TypeName)
+		return obj
+	}
+
+	for _, imported := range f.Object.Pkg().Imports() {
+		if imported.Name() == pkgIdent.Name {
+			obj, _ := imported.Scope().Lookup(sel.Sel.Name).(*types.TypeName)
+			return obj
+		}
+	}
+
+	return nil
+}
+
+//predeclaredZero returns a zero value literal for the predeclared type
+func predeclaredZero(name string) (string, bool) {
+	switch name {
+	case "bool":
+		return "false", true
+	case "string":
+		return `""`, true
+	case "error", "any":
+		return "nil", true
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128", "byte", "rune":
+		return "0", true
+	}
+
+	return "", false
+}
+
+//typedZero returns a zero value literal for the type
+func typedZero(t types.Type, qf types.Qualifier) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+		return "nil"
+	case *types.Pointer:
+		if _, isStruct := u.Elem().Underlying().(*types.Struct); isStruct {
+			return "&" + typedZero(u.Elem(), qf)
+		}
+		return "nil"
+	case *types.Signature, *types.Slice:
+		return "nil"
+	case *types.Interface:
+		if _, isTypeParam := t.(*types.TypeParam); isTypeParam {
+			return "*new(" + types.TypeString(t, qf) + ")"
+		}
+		return "nil"
+	case *types.Map, *types.Struct, *types.Array:
+		return types.TypeString(t, qf) + "{}"
+	case *types.Chan:
+		return "make(" + types.TypeString(t, qf) + ")"
+	}
+
+	return "*new(" + types.TypeString(t, qf) + ")"
+}
@@ -0,0 +1,176 @@
+package gounit
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+)
+
+const zeroSource = `package zero
+
+import "time"
+
+type (
+	MyInt    int
+	MyString string
+	Struct   struct{}
+	Slice    []int
+	Array    [2]int
+	Map      map[string]int
+	Chan     chan int
+	Iface    interface{}
+	Func     func()
+	Derived  Struct
+	List[T any] struct{}
+)
+
+func f(d time.Duration, t time.Time) {}`
+
+func TestFunc_ZeroValue(t *testing.T) {
+	fs, funcs := parseFuncs(t, zeroSource)
+	f := funcs[0]
+
+	tests := []struct {
+		name string
+		typ  interface{}
+
+		want1 string
+	}{
+		{name: "bool", typ: "bool", want1: "false"},
+		{name: "string", typ: "string", want1: `""`},
+		{name: "int declaration", typ: "got1 int", want1: "0"},
+		{name: "pointer declaration", typ: "p *Struct", want1: "&Struct{}"},
+		{name: "error", typ: "error", want1: "nil"},
+		{name: "pointer", typ: "*int", want1: "nil"},
+		{name: "slice", typ: "[]string", want1: "nil"},
+		{name: "array", typ: "[3]int", want1: "[3]int{}"},
+		{name: "map", typ: "map[string]int", want1: "map[string]int{}"},
+		{name: "chan", typ: "chan int", want1: "make(chan int)"},
+		{name: "func", typ: "func(a int) error", want1: "nil"},
+		{name: "named int", typ: "MyInt", want1: "0"},
+		{name: "named string", typ: "MyString", want1: `""`},
+		{name: "named struct", typ: "Struct", want1: "Struct{}"},
+		{name: "named slice", typ: "Slice", want1: "nil"},
+		{name: "named array", typ: "Array", want1: "Array{}"},
+		{name: "named map", typ: "Map", want1: "Map{}"},
+		{name: "named chan", typ: "Chan", want1: "make(Chan)"},
+		{name: "named interface", typ: "Iface", want1: "nil"},
+		{name: "named func", typ: "Func", want1: "nil"},
+		{name: "derived struct", typ: "Derived", want1: "Derived{}"},
+		{name: "generic struct", typ: "List[int]", want1: "List[int]{}"},
+		{name: "unknown type", typ: "Unknown", want1: "*new(Unknown)"},
+		{name: "imported type", typ: "time.Time", want1: "*new(time.Time)"},
+		{name: "ast expression", typ: &ast.Ident{Name: "int"}, want1: "0"},
+		{name: "unsupported value", typ: 1, want1: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := f.ZeroValue(fs, tt.typ)
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.ZeroValue got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestFunc_ZeroValue_typed(t *testing.T) {
+	funcs := typedFuncs(t, zeroSource)
+	f := funcs[0]
+
+	tests := []struct {
+		name string
+		typ  interface{}
+
+		want1 string
+	}{
+		{name: "imported numeric type", typ: "time.Duration", want1: "0"},
+		{name: "imported struct", typ: "time.Time", want1: "time.Time{}"},
+		{name: "resolved param type", typ: f.ParamTypes()[1], want1: "time.Time{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := f.ZeroValue(token.NewFileSet(), tt.typ)
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.ZeroValue got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestFunc_ZeroValue_mock(t *testing.T) {
+	fs, funcs := parseFuncs(t, zeroSource)
+	f := funcs[0]
+	f.testTypes = map[string]bool{"IfaceMock": true}
+
+	if got1 := f.ZeroValue(fs, "Iface"); got1 != "&IfaceMock{}" {
+		t.Errorf("Func.ZeroValue got1 = %v, want1: &IfaceMock{}", got1)
+	}
+}
+
+func TestFunc_WantZero(t *testing.T) {
+	fs, funcs := parseFuncs(t, zeroSource)
+	f := funcs[0]
+	f.testTypes = map[string]bool{"IfaceMock": true}
+
+	tests := []struct {
+		name string
+		typ  interface{}
+
+		want1 string
+	}{
+		{name: "int declaration", typ: "got1 int", want1: "0"},
+		{name: "pointer to struct", typ: "got1 *Struct", want1: "nil"},
+		{name: "map", typ: "map[string]int", want1: "nil"},
+		{name: "chan", typ: "chan int", want1: "nil"},
+		{name: "slice", typ: "[]string", want1: "nil"},
+		{name: "array", typ: "[3]int", want1: "[3]int{}"},
+		{name: "named struct", typ: "Struct", want1: "Struct{}"},
+		{name: "named map", typ: "Map", want1: "nil"},
+		{name: "named chan", typ: "Chan", want1: "nil"},
+		{name: "named interface with mock", typ: "Iface", want1: "nil"},
+		{name: "generic struct", typ: "List[int]", want1: "List[int]{}"},
+		{name: "unknown type", typ: "Unknown", want1: ""},
+		{name: "imported type", typ: "io.Reader", want1: ""},
+		{name: "unsupported value", typ: 1, want1: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := f.WantZero(fs, tt.typ)
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.WantZero got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestFunc_WantZero_typed(t *testing.T) {
+	f := typedFuncs(t, "package zero\n\nimport (\n\t\"io\"\n\t\"time\"\n)\n\nfunc f(r io.Reader, t time.Time) {}")[0]
+
+	tests := []struct {
+		name string
+		typ  interface{}
+
+		want1 string
+	}{
+		{name: "imported interface", typ: "io.Reader", want1: "nil"},
+		{name: "imported struct", typ: "time.Time", want1: "time.Time{}"},
+		{name: "pointer to imported struct", typ: "*time.Time", want1: "nil"},
+		{name: "resolved param type", typ: f.ParamTypes()[0], want1: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := f.WantZero(token.NewFileSet(), tt.typ)
+
+			if got1 != tt.want1 {
+				t.Errorf("Func.WantZero got1 = %v, want1: %v", got1, tt.want1)
+			}
+		})
+	}
+}